@@ -22,6 +22,7 @@ func New(cfg *config.Config) *cobra.Command {
 		colour     bool
 		plain      bool
 		retries    int64
+		selector   string
 	)
 	cmd := &cobra.Command{
 		Use:          "check",
@@ -30,15 +31,25 @@ func New(cfg *config.Config) *cobra.Command {
 		Long:         `Run the checks once and get an exit code.`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			chkr, err := checker.NewFromConfig(*cfg, false)
+			// the state is only persisted, and notifications are only sent, when running as a service
+			checksCfg := *cfg
+			checksCfg.Store = config.StoreCfg{}
+			checksCfg.Notifiers = config.NotifiersCfg{}
+			chkr, err := checker.NewFromConfig(checksCfg, false)
 			if err != nil {
 				return err
 			}
 
+			labels, err := checker.ParseLabelSelector(selector)
+			if err != nil {
+				return err
+			}
+			sel := checker.Selector{Labels: labels}
+
 			var anyFailed bool
 			retries += 1
 			for i := retries; i > 0; i-- {
-				chkr.Check(context.Background())
+				chkr.CheckSelected(context.Background(), sel)
 				_, anyFailed = chkr.Summary()
 				if !anyFailed || i <= 1 {
 					break
@@ -92,6 +103,7 @@ func New(cfg *config.Config) *cobra.Command {
 	cmd.Flags().BoolVarP(&colour, "colour", "C", true, "print the check status in colour")
 	cmd.Flags().BoolVarP(&plain, "plain", "P", false, "disable both pretty printing and colour")
 	cmd.Flags().Int64VarP(&retries, "retries", "r", 0, "number of times to retry on failure")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "only run the checks matching the given labels, e.g.: team=payments,tier=1")
 
 	return cmd
 }
@@ -0,0 +1,77 @@
+package serve
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+
+	"github.com/luisdavim/synthetic-checker/pkg/checker"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// reloadConfig reads the checks configuration again and applies the changes to the runner
+func reloadConfig(v *viper.Viper, chkr *checker.Runner) {
+	if err := v.ReadInConfig(); err != nil {
+		log.Printf("error reading checks config: %v", err)
+		return
+	}
+	var cfg config.Config
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(config.DecodeHook())); err != nil {
+		log.Printf("error reading checks config: %v", err)
+		return
+	}
+	if err := chkr.Reload(cfg); err != nil {
+		log.Printf("error reloading checks config: %v", err)
+	}
+}
+
+// watchConfig reloads the checks configuration when a SIGHUP is received
+// and, optionally, when the config file changes, including ConfigMap updates that swap symlinks.
+// Viper is not safe for concurrent use, so the file is watched and read by separate viper instances
+// and all the reloads are done, one at a time, by a single goroutine.
+func watchConfig(chkr *checker.Runner, watchFile bool) {
+	file := viper.ConfigFileUsed()
+	if file == "" {
+		return
+	}
+
+	reload := make(chan string, 1)
+	trigger := func(reason string) {
+		select {
+		case reload <- reason:
+		default:
+			// a reload is already pending, it will read the latest config
+		}
+	}
+
+	if watchFile {
+		watcher := viper.New()
+		watcher.SetConfigFile(file)
+		watcher.OnConfigChange(func(e fsnotify.Event) {
+			trigger("config file changed: " + e.Name)
+		})
+		watcher.WatchConfig()
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		for range sighup {
+			trigger("SIGHUP received")
+		}
+	}()
+
+	go func() {
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.AutomaticEnv()
+		for reason := range reload {
+			log.Printf("%s, reloading config", reason)
+			reloadConfig(v, chkr)
+		}
+	}()
+}
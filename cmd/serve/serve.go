@@ -21,6 +21,7 @@ type options struct {
 	degradedStatus int
 	haMode         bool
 	watchIngresses bool
+	watchConfig    bool
 	leID           string
 	leNs           string
 }
@@ -71,6 +72,8 @@ func New(cfg *config.Config) *cobra.Command {
 				}
 			}
 
+			watchConfig(chkr, opts.watchConfig)
+
 			srv := checksapi.New(chkr, srvCfg, opts.failStatus, opts.degradedStatus)
 			srv.Run()
 			return nil
@@ -79,12 +82,13 @@ func New(cfg *config.Config) *cobra.Command {
 
 	server.Init(cmd)
 
-	cmd.Flags().IntVarP(&opts.failStatus, "failed-status-code", "F", http.StatusOK, "HTTP status code to return when all checks are failed")
-	cmd.Flags().IntVarP(&opts.degradedStatus, "degraded-status-code", "D", http.StatusOK, "HTTP status code to return when check check is failed")
+	cmd.Flags().IntVarP(&opts.failStatus, "failed-status-code", "F", http.StatusOK, "HTTP status code to return when the checks are evaluated as failed")
+	cmd.Flags().IntVarP(&opts.degradedStatus, "degraded-status-code", "D", http.StatusOK, "HTTP status code to return when the checks are evaluated as degraded")
 	cmd.Flags().BoolVarP(&opts.haMode, "k8s-leader-election", "", false, "Enable leader election, only works when running in k8s")
 	cmd.Flags().StringVarP(&opts.leID, "leader-election-id", "", "", "set the leader election ID, defaults to POD_NAME or hostname")
 	cmd.Flags().StringVarP(&opts.leNs, "leader-election-ns", "", "", "set the leader election namespace, defaults to the current namespace")
 	cmd.Flags().BoolVarP(&opts.watchIngresses, "watch-ingresses", "w", false, "Automatically setup checks for k8s ingresses, only works when running in k8s")
+	cmd.Flags().BoolVarP(&opts.watchConfig, "watch-config", "", true, "Reload the checks when the config file changes, the config is also reloaded on SIGHUP")
 
 	return cmd
 }
@@ -1,6 +1,10 @@
 package api
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
 
 func TestEvaluate(t *testing.T) {
 	type expected struct {
@@ -51,6 +55,74 @@ func TestEvaluate(t *testing.T) {
 				anyFailed: true,
 			},
 		},
+		{
+			name: "failed and skipped",
+			status: Statuses{
+				"foo": {
+					OK: false,
+				},
+				"bar": {
+					OK:      false,
+					Skipped: true,
+					Reason:  ReasonDependencyFailed,
+				},
+				"baz": {
+					OK: true,
+				},
+			},
+			expected: expected{
+				allFailed: false,
+				anyFailed: true,
+			},
+		},
+		{
+			name: "only silenced failing",
+			status: Statuses{
+				"foo": {
+					OK: true,
+				},
+				"bar": {
+					OK:       false,
+					Silenced: true,
+					Reason:   ReasonSilenced,
+				},
+			},
+			expected: expected{
+				allFailed: false,
+				anyFailed: false,
+			},
+		},
+		{
+			name: "all silenced",
+			status: Statuses{
+				"foo": {
+					OK:       false,
+					Silenced: true,
+					Reason:   ReasonSilenced,
+				},
+			},
+			expected: expected{
+				allFailed: false,
+				anyFailed: false,
+			},
+		},
+		{
+			name: "only skipped failing",
+			status: Statuses{
+				"foo": {
+					OK: true,
+				},
+				"bar": {
+					OK:      false,
+					Skipped: true,
+					Reason:  ReasonDependencyFailed,
+				},
+			},
+			expected: expected{
+				allFailed: false,
+				anyFailed: false,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -62,3 +134,95 @@ func TestEvaluate(t *testing.T) {
 		})
 	}
 }
+
+func TestSummarize(t *testing.T) {
+	status := Statuses{
+		"a": {OK: true},
+		"b": {OK: true},
+		"c": {OK: false},
+		"d": {OK: false},
+		"e": {OK: false, Silenced: true},
+	}
+	tests := []struct {
+		name     string
+		status   Statuses
+		policy   config.EvaluationPolicy
+		expected Health
+	}{
+		{
+			name:     "all OK",
+			status:   Statuses{"a": {OK: true}},
+			expected: HealthOK,
+		},
+		{
+			name:     "default policy",
+			status:   status,
+			expected: HealthDegraded,
+		},
+		{
+			name:     "all KO",
+			status:   Statuses{"a": {OK: false}, "b": {OK: false}},
+			expected: HealthFailed,
+		},
+		{
+			name:     "failed from half",
+			status:   status,
+			policy:   config.EvaluationPolicy{FailedPercent: 50},
+			expected: HealthFailed,
+		},
+		{
+			name:     "degraded threshold not reached",
+			status:   status,
+			policy:   config.EvaluationPolicy{DegradedPercent: 60},
+			expected: HealthOK,
+		},
+		{
+			name:     "no checks",
+			status:   Statuses{},
+			expected: HealthFailed,
+		},
+		{
+			name:     "critical KO",
+			status:   Statuses{"a": {OK: true}, "b": {OK: false, Critical: true}},
+			expected: HealthFailed,
+		},
+		{
+			name:     "critical OK and optional KO",
+			status:   Statuses{"a": {OK: false}, "b": {OK: true, Critical: true}, "c": {OK: true}},
+			expected: HealthDegraded,
+		},
+		{
+			name:     "critical failures threshold not reached",
+			status:   Statuses{"a": {OK: false, Critical: true}, "b": {OK: true, Critical: true}, "c": {OK: true}},
+			policy:   config.EvaluationPolicy{CriticalFailures: 2},
+			expected: HealthDegraded,
+		},
+		{
+			name:     "warning",
+			status:   Statuses{"a": {OK: true}, "b": {OK: true, Warning: true}},
+			expected: HealthDegraded,
+		},
+		{
+			name:     "silenced critical KO",
+			status:   Statuses{"a": {OK: true}, "b": {OK: false, Critical: true, Silenced: true}},
+			expected: HealthOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := tt.status.Summarize(tt.policy)
+			if summary.Status != tt.expected {
+				t.Errorf("unexpected status, wanted: %s, got: %s", tt.expected, summary.Status)
+			}
+			if summary.Total != len(tt.status) || summary.Passing+summary.Failing+summary.Ignored != summary.Total {
+				t.Errorf("unexpected counts: %+v", summary)
+			}
+		})
+	}
+
+	summary := status.Summarize(config.EvaluationPolicy{})
+	if summary.FailingOptionalPercent != 50 || summary.Ignored != 1 || summary.Failing != 2 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
@@ -2,14 +2,23 @@ package api
 
 import (
 	"context"
+	"errors"
+	"sort"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
+// ErrWarning can be wrapped by the error returned along with a successful result
+// to report that the check passed but is close to failing, e.g.: a certificate about to expire
+var ErrWarning = errors.New("warning")
+
 // Check defines the api for implementing a checker
 type Check interface {
-	// Checkers must implement an Execute function that runs the check and returns the status
+	// Checkers must implement an Execute function that runs the check and returns the status,
+	// an error wrapping ErrWarning can be returned along with true to report a warning
 	Execute(ctx context.Context) (bool, error)
 	// Checkers must implement an Interval function that indicates how often the check should run
 	Interval() metav1.Duration
@@ -17,37 +26,296 @@ type Check interface {
 	InitialDelay() metav1.Duration
 	// Checkers must implement a Config method that returns the check type, name and configuration
 	Config() (string, string, string, error)
+	// Checkers must implement a BaseConfig method that returns the settings common to all checks
+	BaseConfig() config.BaseCheck
+}
+
+// Targeted can be implemented by checks that probe a remote host
+type Targeted interface {
+	// Target returns the host being checked
+	Target() string
 }
 
 type Checks map[string]Check
 
+const (
+	// ReasonDependencyFailed indicates the check was skipped because one of its dependencies is failing
+	ReasonDependencyFailed = "DependencyFailed"
+	// ReasonPending indicates the check was skipped because the checks it depends on haven't run yet
+	ReasonPending = "Pending"
+	// ReasonOutsideActiveWindow indicates the check was skipped because it was due outside of its active windows
+	ReasonOutsideActiveWindow = "OutsideActiveWindow"
+	// ReasonTimeout indicates the check failed because it didn't complete within its timeout
+	ReasonTimeout = "Timeout"
+	// ReasonSilenced indicates the check matches an active silence
+	ReasonSilenced = "Silenced"
+	// ReasonDurationExceeded indicates that the check took longer than its maxDuration or warnDuration
+	ReasonDurationExceeded = "DurationExceeded"
+)
+
 // Status represents the state of what is being checked
 type Status struct {
-	// OK indicates if the last check passed
+	// OK indicates if the check is passing, taking into account the configured failure and success thresholds
 	OK bool `json:"ok,omitempty"`
-	// Error holds an error message explaining why the check failed
+	// LastOK indicates if the last execution of the check passed, regardless of the thresholds
+	LastOK bool `json:"lastOK,omitempty"`
+	// Error holds an error message explaining why the check failed or the warning
 	Error string `json:"error,omitempty"`
+	// Warning indicates that the last execution of the check passed with a warning
+	Warning bool `json:"warning,omitempty"`
 	// Timestamp indicates when the check was last run
 	Timestamp time.Time `json:"timestamp"`
 	// Duration indicates how long the last check took to run
 	Duration metav1.Duration `json:"duration,omitempty"`
+	// Attempts indicates how many times the check was executed, including retries, the last time it ran
+	Attempts int `json:"attempts,omitempty"`
 	// ContiguousFailures indicates the number of failures that occurred in a row
 	ContiguousFailures int `json:"contiguousFailures"`
+	// ContiguousSuccesses indicates the number of successes that occurred in a row
+	ContiguousSuccesses int `json:"contiguousSuccesses"`
 	// TimeOfFirstFailure indicates when the first failure occurred
 	TimeOfFirstFailure time.Time `json:"timeOfFirstFailure"`
+	// Skipped indicates that the check was not executed the last time it was due, Reason explains why
+	Skipped bool `json:"skipped,omitempty"`
+	// Silenced indicates that the check matched an active silence the last time it was due
+	Silenced bool `json:"silenced,omitempty"`
+	// Reason is a machine readable explanation for the current state of the check
+	Reason string `json:"reason,omitempty"`
+	// Labels are the labels configured for the check
+	Labels map[string]string `json:"labels,omitempty"`
+	// Owner identifies who is responsible for the check
+	Owner string `json:"owner,omitempty"`
+	// Severity indicates how important a failure of the check is
+	Severity string `json:"severity,omitempty"`
+	// Critical checks make the overall status failed as soon as they fail
+	Critical bool `json:"critical,omitempty"`
+	// Description explains what the check is about
+	Description string `json:"description,omitempty"`
+	// RunbookURL points to the instructions on what to do when the check fails
+	RunbookURL string `json:"runbookURL,omitempty"`
 }
 
 type Statuses map[string]Status
 
 // Evaluate checks if any or all checks are reported as failed
+// skipped and silenced checks are not taken into account
 func (status Statuses) Evaluate() (allFailed, anyFailed bool) {
 	allFailed = true
+	ignored := 0
 	for _, result := range status {
+		if result.Skipped || result.Silenced {
+			ignored++
+			continue
+		}
 		if !result.OK {
 			anyFailed = true
 		} else {
 			allFailed = false
 		}
 	}
+	// there's nothing failing if all the checks are ignored
+	if len(status) > 0 && ignored == len(status) {
+		allFailed = false
+	}
 	return
 }
+
+// Health is the overall status of a set of checks
+type Health string
+
+const (
+	HealthOK       Health = "ok"
+	HealthDegraded Health = "degraded"
+	HealthFailed   Health = "failed"
+)
+
+// Summary is the result of evaluating a set of statuses
+type Summary struct {
+	// Status is the overall status according to the evaluation policy
+	Status Health `json:"status"`
+	// Total is the number of checks, including the ignored ones
+	Total int `json:"total"`
+	// Passing is the number of checks that are OK
+	Passing int `json:"passing"`
+	// Failing is the number of checks that are failing
+	Failing int `json:"failing"`
+	// Warnings is the number of passing checks that reported a warning
+	Warnings int `json:"warnings"`
+	// Ignored is the number of skipped and silenced checks
+	Ignored int `json:"ignored"`
+	// FailingCritical lists the critical checks that are failing
+	FailingCritical []string `json:"failingCritical,omitempty"`
+	// FailingOptionalPercent is the percentage of optional checks that are failing
+	FailingOptionalPercent int `json:"failingOptionalPercent"`
+}
+
+// Report is a set of statuses along with their summary
+type Report struct {
+	Summary Summary  `json:"summary"`
+	Checks  Statuses `json:"checks"`
+}
+
+// Summarize evaluates the statuses according to the given policy, passing checks with warnings make the status degraded,
+// like Evaluate, an empty set of statuses is considered failed
+func (status Statuses) Summarize(policy config.EvaluationPolicy) Summary {
+	summary := Summary{Total: len(status)}
+	optional, optionalFailing := 0, 0
+	for name, result := range status {
+		if result.Skipped || result.Silenced {
+			summary.Ignored++
+			continue
+		}
+		if result.OK {
+			summary.Passing++
+			if result.Warning {
+				summary.Warnings++
+			}
+		} else {
+			summary.Failing++
+		}
+		if result.Critical {
+			if !result.OK {
+				summary.FailingCritical = append(summary.FailingCritical, name)
+			}
+			continue
+		}
+		optional++
+		if !result.OK {
+			optionalFailing++
+		}
+	}
+	sort.Strings(summary.FailingCritical)
+	if optional > 0 {
+		summary.FailingOptionalPercent = optionalFailing * 100 / optional
+	}
+
+	failedPercent := policy.FailedPercent
+	if failedPercent == 0 {
+		failedPercent = 100
+	}
+	criticalFailures := policy.CriticalFailures
+	if criticalFailures == 0 {
+		criticalFailures = 1
+	}
+	switch {
+	case len(status) == 0,
+		len(summary.FailingCritical) >= criticalFailures,
+		optionalFailing > 0 && summary.FailingOptionalPercent >= failedPercent:
+		summary.Status = HealthFailed
+	case len(summary.FailingCritical) > 0,
+		summary.Warnings > 0,
+		optionalFailing > 0 && summary.FailingOptionalPercent >= policy.DegradedPercent:
+		summary.Status = HealthDegraded
+	default:
+		summary.Status = HealthOK
+	}
+	return summary
+}
+
+// EventType identifies the kind of change reported by an Event
+type EventType string
+
+const (
+	// EventCheckAdded is emitted when a new check is added
+	EventCheckAdded EventType = "CheckAdded"
+	// EventCheckUpdated is emitted when the configuration of an existing check changes
+	EventCheckUpdated EventType = "CheckUpdated"
+	// EventCheckDeleted is emitted when a check is removed
+	EventCheckDeleted EventType = "CheckDeleted"
+	// EventStatusChanged is emitted when a check goes from OK to failed or back, taking into account the thresholds,
+	// checks without a previous status are considered OK, it's also emitted when a silence of the check ends
+	EventStatusChanged EventType = "StatusChanged"
+	// EventFirstFailure is emitted when a check fails after succeeding, regardless of the thresholds
+	EventFirstFailure EventType = "FirstFailure"
+	// EventRecovered is emitted when a check succeeds after failing, regardless of the thresholds
+	EventRecovered EventType = "Recovered"
+)
+
+// Event reports a change in the checks or their statuses
+type Event struct {
+	// Type is the kind of change
+	Type EventType `json:"type"`
+	// Name is the check name, as reported in the status, e.g.: "example-http"
+	Name string `json:"name"`
+	// Timestamp indicates when the change happened
+	Timestamp time.Time `json:"timestamp"`
+	// Status is the check status after the change, it's only set for status events
+	Status *Status `json:"status,omitempty"`
+	// Failures is the number of contiguous failures the check recovered from, it's only set for recovered events
+	Failures int `json:"failures,omitempty"`
+}
+
+// Result represents a single execution of a check
+type Result struct {
+	// Timestamp indicates when the check was run
+	Timestamp time.Time `json:"timestamp"`
+	// OK indicates if the execution passed
+	OK bool `json:"ok,omitempty"`
+	// Warning indicates that the execution passed with a warning
+	Warning bool `json:"warning,omitempty"`
+	// Error holds an error message explaining why the check failed
+	Error string `json:"error,omitempty"`
+	// Duration indicates how long the check took to run
+	Duration metav1.Duration `json:"duration,omitempty"`
+	// Skipped indicates that the check was not executed, Reason explains why
+	Skipped bool `json:"skipped,omitempty"`
+	// Silenced indicates that the check matched an active silence
+	Silenced bool `json:"silenced,omitempty"`
+	// Reason is a machine readable explanation for the result
+	Reason string `json:"reason,omitempty"`
+}
+
+// Result returns the result of the last execution recorded in the status
+func (status Status) Result() Result {
+	return Result{
+		Timestamp: status.Timestamp,
+		OK:        status.LastOK,
+		Warning:   status.Warning,
+		Error:     status.Error,
+		Duration:  status.Duration,
+		Skipped:   status.Skipped,
+		Silenced:  status.Silenced,
+		Reason:    status.Reason,
+	}
+}
+
+// Percentiles holds the distribution of the check durations
+type Percentiles struct {
+	P50 metav1.Duration `json:"p50"`
+	P90 metav1.Duration `json:"p90"`
+	P95 metav1.Duration `json:"p95"`
+	P99 metav1.Duration `json:"p99"`
+}
+
+// History represents the recorded results of a check
+type History struct {
+	// Results are the recorded results, oldest first
+	Results []Result `json:"results"`
+	// Uptime is the percentage of successful executions over the last 1h, 24h, 7d and 30d, skipped and silenced executions are ignored,
+	// windows without any executions, or not fully covered by the recorded results, are omitted
+	Uptime map[string]float64 `json:"uptime"`
+	// Latency holds the percentiles of the durations of the recorded executions
+	Latency *Percentiles `json:"latency,omitempty"`
+	// SLO holds the state of the check's service level objective, if any
+	SLO *SLOStatus `json:"slo,omitempty"`
+}
+
+// SLOStatus represents the state of a check's service level objective,
+// skipped and silenced executions are not taken into account
+type SLOStatus struct {
+	// Target is the percentage of executions that must succeed
+	Target float64 `json:"target"`
+	// Window is the period over which the target is evaluated
+	Window metav1.Duration `json:"window"`
+	// EffectiveWindow is the part of the window covered by the recorded results,
+	// only set when the history size or retention don't allow covering the whole window
+	EffectiveWindow *metav1.Duration `json:"effectiveWindow,omitempty"`
+	// SLI is the percentage of successful executions over the window, 100 when there are no executions
+	SLI float64 `json:"sli"`
+	// ErrorBudgetRemaining is the ratio of the error budget left over the window,
+	// 1 when there were no failures and negative when the budget is exhausted
+	ErrorBudgetRemaining float64 `json:"errorBudgetRemaining"`
+	// BurnRates is how fast the error budget is being consumed over the last 5m, 30m, 1h, 6h, 1d and 3d,
+	// 1 means the budget would be exhausted exactly at the end of the window, windows without any executions are omitted
+	BurnRates map[string]float64 `json:"burnRates"`
+}
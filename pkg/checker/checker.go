@@ -3,39 +3,94 @@ package checker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/luisdavim/synthetic-checker/pkg/api"
 	"github.com/luisdavim/synthetic-checker/pkg/checks"
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 	"github.com/luisdavim/synthetic-checker/pkg/informer"
+	"github.com/luisdavim/synthetic-checker/pkg/notifier"
+	"github.com/luisdavim/synthetic-checker/pkg/store"
 )
 
 var (
+	checkCount    *prometheus.CounterVec
+	checkStatus   *prometheus.GaugeVec
+	checkDuration *prometheus.HistogramVec
+	checkTimeouts *prometheus.CounterVec
+
+	checkSLOBudget   *prometheus.GaugeVec
+	checkSLOBurnRate *prometheus.GaugeVec
+)
+
+// initMetrics creates the check metrics, the given check labels are added to each of them
+func initMetrics(labels []string) {
+	names := []string{"name"}
+	for _, l := range labels {
+		names = append(names, config.MetricLabelName(l))
+	}
+
 	checkCount = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "check_status_total",
 		Help: "Number of check status occurences",
-	}, []string{"name", "status"})
+	}, append([]string{"status"}, names...))
 
 	checkStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "check_status_up",
-		Help: "Status from the check",
-	}, []string{"name"})
+		Help: "Status from the check, 0 when failed, 1 when OK and 2 when OK with a warning",
+	}, names)
 
 	checkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name:    "check_duration_ms",
 		Help:    "Duration of the check",
 		Buckets: []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
-	}, []string{"name"})
+	}, names)
+
+	checkTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "check_timeouts_total",
+		Help: "Number of check executions that exceeded their timeout",
+	}, names)
+
+	checkSLOBudget = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "check_slo_error_budget_remaining",
+		Help: "Ratio of the error budget left over the SLO window, negative when exhausted",
+	}, names)
+
+	checkSLOBurnRate = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "check_slo_burn_rate",
+		Help: "Rate at which the error budget is being consumed over the given window",
+	}, append([]string{"window"}, names...))
+}
+
+var (
+	// ErrCheckNotFound is returned when the given check name doesn't match any configured check
+	ErrCheckNotFound = errors.New("check not found")
+	// ErrNotHeartbeat is returned when trying to send a heartbeat to an active check
+	ErrNotHeartbeat = errors.New("not a heartbeat check")
+	// ErrTimeout is returned when a check doesn't complete within its configured timeout
+	ErrTimeout = errors.New("check timed out")
+	// ErrDurationExceeded is returned when a check succeeds but takes longer than its configured maxDuration
+	ErrDurationExceeded = errors.New("check duration exceeded")
+	// ErrSilenceNotFound is returned when the given silence name doesn't match any existing silence
+	ErrSilenceNotFound = errors.New("silence not found")
+	// ErrInformOnly is returned when trying to execute checks in an instance that only pushes them upstream
+	ErrInformOnly = errors.New("checks are not executed by this instance")
+	// ErrGroupNotFound is returned when the given group name doesn't match any configured group
+	ErrGroupNotFound = errors.New("group not found")
 )
 
 // Runner reprents the main checks runner (checker)
@@ -49,25 +104,82 @@ type Runner struct {
 	leader          string
 	upstreamRefresh time.Duration
 	informOnly      bool
+	started         bool
+	limiter         *limiter
+	silences        map[string]config.Silence
+	groups          map[string]config.Group
+	evaluation      config.EvaluationPolicy
+	history         map[string]*history
+	historyCfg      config.HistoryCfg
+	store           store.Store
+	cfg             config.Config // the last configuration loaded from the config file
+	reloadMu        sync.Mutex
+	metricLabels    []string
+	events          *eventBus
+	notifier        *notifier.Notifier
+	running         sync.Map                 // a mutex per check name, so that each check is executed at most once at a time
+	ready           map[string]chan struct{} // closed once the given check has run, see statusReady
 	sync.RWMutex
 }
 
 // NewFromConfig creates a check runner from the given configuration
 func NewFromConfig(cfg config.Config, start bool) (*Runner, error) {
-	prometheus.MustRegister(checkStatus, checkCount, checkDuration)
+	if err := cfg.Metrics.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid metrics config: %w", err)
+	}
+	initMetrics(cfg.Metrics.Labels)
+	prometheus.MustRegister(checkStatus, checkCount, checkDuration, checkTimeouts, checkQueueLength, checkSchedulingDelay, checkSLOBudget, checkSLOBurnRate)
 	r := &Runner{
-		checks: make(api.Checks),
-		status: make(api.Statuses),
-		stop:   make(map[string](chan struct{})),
-		log:    zerolog.New(os.Stderr).With().Timestamp().Str("name", "checker").Logger().Level(zerolog.InfoLevel),
+		checks:       make(api.Checks),
+		status:       make(api.Statuses),
+		stop:         make(map[string](chan struct{})),
+		log:          zerolog.New(os.Stderr).With().Timestamp().Str("name", "checker").Logger().Level(zerolog.InfoLevel),
+		limiter:      newLimiter(cfg.Concurrency),
+		silences:     make(map[string]config.Silence),
+		history:      make(map[string]*history),
+		ready:        make(map[string]chan struct{}),
+		historyCfg:   cfg.History,
+		cfg:          cfg,
+		metricLabels: cfg.Metrics.Labels,
+	}
+	r.events = newEventBus(r.log)
+
+	r.started = start
+	if err := r.SetGroups(cfg.Groups); err != nil {
+		return nil, err
+	}
+	if err := r.SetEvaluation(cfg.Evaluation); err != nil {
+		return nil, err
+	}
+	var err error
+	r.notifier, err = notifier.New(cfg.Notifiers)
+	if err != nil {
+		return nil, err
+	}
+	r.notifier.Start(r)
+	r.store, err = store.New(cfg.Store, cfg.History)
+	if err != nil {
+		return nil, err
+	}
+	if r.store != nil {
+		if err := r.restoreState(start); err != nil {
+			return nil, fmt.Errorf("failed to restore the state: %w", err)
+		}
 	}
 
 	if err := r.AddFromConfig(cfg, start); err != nil {
 		return nil, err
 	}
 
+	if r.store != nil {
+		if err := r.restoreHeartbeats(); err != nil {
+			return nil, fmt.Errorf("failed to restore the heartbeats: %w", err)
+		}
+		// the notifier was started before the statuses were restored, seed it again so that it keeps, e.g.: the alerts of the failing checks
+		r.notifier.Seed(r.GetStatus())
+	}
+
 	if len(cfg.Informer.Upstreams) > 0 {
-		var err error
 		r.informer, err = informer.New(cfg.Informer.Upstreams)
 		if err != nil {
 			return nil, err
@@ -82,71 +194,139 @@ func NewFromConfig(cfg config.Config, start bool) (*Runner, error) {
 	return r, nil
 }
 
-// AddFromConfig loads the checks from the given configuration
+// AddFromConfig loads the checks from the given configuration,
+// no checks are added if any of them is invalid
 func (r *Runner) AddFromConfig(cfg config.Config, start bool) error {
+	staged, err := r.newChecks(cfg)
+	if err != nil {
+		return err
+	}
+	for name, check := range staged {
+		r.AddCheck(name, check, start)
+	}
+	r.warnUnknownDependencies()
+
+	// setup silences
+	for name, silence := range cfg.Silences {
+		if err := r.AddSilence(name, silence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newChecks creates the checks from the given configuration, without adding them to the runner
+func (r *Runner) newChecks(cfg config.Config) (api.Checks, error) {
+	staged := make(api.Checks)
+
 	// setup HTTP checks
 	for name, config := range cfg.HTTPChecks {
 		check, err := checks.NewHTTPCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-http", check, start)
+		staged[name+"-http"] = check
 	}
 
 	// setup DNS checks
 	for name, config := range cfg.DNSChecks {
 		check, err := checks.NewDNSCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-dns", check, start)
+		staged[name+"-dns"] = check
 	}
 
 	// setup K8s checks
 	for name, config := range cfg.K8sChecks {
 		check, err := checks.NewK8sCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-k8s", check, start)
+		staged[name+"-k8s"] = check
 	}
 
 	// setup K8s pings
 	for name, config := range cfg.K8sPings {
 		check, err := checks.NewK8sPing(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-k8sping", check, start)
+		staged[name+"-k8sping"] = check
 	}
 
 	// setup conn checks
 	for name, config := range cfg.ConnChecks {
 		check, err := checks.NewConnCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-conn", check, start)
+		staged[name+"-conn"] = check
 	}
 
 	// setup TLS checks
 	for name, config := range cfg.TLSChecks {
-		var err error
-		r.checks[name+"-tls"], err = checks.NewTLSCheck(name, config)
+		check, err := checks.NewTLSCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		staged[name+"-tls"] = check
 	}
 
 	// setup gRPC checks
 	for name, config := range cfg.GRPCChecks {
 		check, err := checks.NewGrpcCheck(name, config)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.AddCheck(name+"-grpc", check, start)
+		staged[name+"-grpc"] = check
 	}
-	return nil
+
+	// setup WebSocket checks
+	for name, config := range cfg.WebSocketChecks {
+		check, err := checks.NewWebSocketCheck(name, config)
+		if err != nil {
+			return nil, err
+		}
+		staged[name+"-websocket"] = check
+	}
+
+	// setup GraphQL checks
+	for name, config := range cfg.GraphQLChecks {
+		check, err := checks.NewGraphQLCheck(name, config)
+		if err != nil {
+			return nil, err
+		}
+		staged[name+"-graphql"] = check
+	}
+
+	// setup heartbeat checks
+	for name, config := range cfg.HeartbeatChecks {
+		check, err := checks.NewHeartbeatCheck(name, config)
+		if err != nil {
+			return nil, err
+		}
+		staged[name+"-heartbeat"] = check
+	}
+
+	// setup Prometheus query checks
+	for name, config := range cfg.PromQueryChecks {
+		check, err := checks.NewPromQueryCheck(name, config)
+		if err != nil {
+			return nil, err
+		}
+		staged[name+"-promquery"] = check
+	}
+
+	// setup composite checks
+	for name, config := range cfg.CompositeChecks {
+		check, err := checks.NewCompositeCheck(name, config, r.GetStatusFor)
+		if err != nil {
+			return nil, err
+		}
+		staged[name+"-composite"] = check
+	}
+	return staged, nil
 }
 
 // AddCheck schedules a new check
@@ -158,11 +338,24 @@ func (r *Runner) AddCheck(name string, check api.Check, start bool) {
 	r.Lock()
 	cur, found := r.checks[name]
 	r.checks[name] = check
-	if !found && start {
+	if stopCh, running := r.stop[name]; found && running && !sameConfig(cur, check) {
+		// restart the scheduling so that changes to the interval or schedule take effect
+		r.log.Info().Str("name", name).Msg("restarting updated check")
+		close(stopCh)
+		delete(r.stop, name)
+		start = !r.informOnly
+	}
+	if _, running := r.stop[name]; !running && start {
 		r.stop[name] = make(chan struct{})
 		r.schedule(context.Background(), name)
 	}
 	r.Unlock()
+	switch {
+	case !found:
+		r.emitCheckEvent(api.EventCheckAdded, name)
+	case !sameConfig(cur, check):
+		r.emitCheckEvent(api.EventCheckUpdated, name)
+	}
 	if r.informer != nil && (!found || !cmp.Equal(&cur, &check)) {
 		err := r.informer.CreateOrUpdate(check)
 		r.log.Err(err).Str("name", name).Msg("syncing check upstream")
@@ -181,18 +374,142 @@ func (r *Runner) DelCheck(name string) {
 	delete(r.stop, name)
 	delete(r.checks, name)
 	delete(r.status, name)
+	delete(r.history, name)
+	if ch, ok := r.ready[name]; ok {
+		// release the checks waiting for it, it won't run anymore
+		close(ch)
+		delete(r.ready, name)
+	}
 	r.Unlock()
+	r.running.Delete(name)
+	if found {
+		r.emitCheckEvent(api.EventCheckDeleted, name)
+	}
+	if r.store != nil {
+		err := r.store.Delete(name)
+		r.log.Err(err).Str("name", name).Msg("deleting persisted check state")
+	}
 	if r.informer != nil && found {
 		err := r.informer.DeleteByName(name)
 		r.log.Err(err).Str("name", name).Msg("deleting check upstream")
 	}
 }
 
+// Heartbeat feeds the given event to a passive heartbeat check and refreshes its status
+func (r *Runner) Heartbeat(ctx context.Context, name string, event checks.HeartbeatEvent, exitCode int) error {
+	r.RLock()
+	check, found := r.checks[name]
+	r.RUnlock()
+	if !found {
+		return fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+	}
+	hb, ok := check.(checks.Heartbeat)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotHeartbeat, name)
+	}
+	r.log.Info().Str("name", name).Str("event", string(event)).Int("exitCode", exitCode).Msg("heartbeat received")
+	hb.Ping(event, exitCode)
+	if r.store != nil {
+		if err := r.store.SaveHeartbeat(name, hb.State()); err != nil {
+			r.log.Err(err).Str("name", name).Msg("persisting heartbeat")
+		}
+	}
+	if !r.informOnly {
+		r.check(ctx, name, check)
+	}
+	return nil
+}
+
+// GetHistoryFor returns the recorded results for the given check, along with its uptime, latency percentiles and SLO
+func (r *Runner) GetHistoryFor(name string) (api.History, bool) {
+	r.RLock()
+	h, ok := r.history[name]
+	r.RUnlock()
+	if !ok {
+		return api.History{}, false
+	}
+	report := h.report(time.Now())
+	if slo, ok := r.GetSLOFor(name); ok {
+		report.SLO = &slo
+	}
+	return report, true
+}
+
+// AddSilence creates or replaces the given silence
+func (r *Runner) AddSilence(name string, silence config.Silence) error {
+	if name == "" {
+		return fmt.Errorf("silence name must not be empty")
+	}
+	if err := silence.Validate(); err != nil {
+		return err
+	}
+	if silence.Mode == "" {
+		silence.Mode = config.SilenceModeSilence
+	}
+	if silence.StartsAt.IsZero() {
+		silence.StartsAt = time.Now()
+	}
+	r.log.Info().Str("name", name).Msg("new silence")
+	r.Lock()
+	r.silences[name] = silence
+	r.Unlock()
+	return nil
+}
+
+// DelSilence removes the given silence
+func (r *Runner) DelSilence(name string) error {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.silences[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrSilenceNotFound, name)
+	}
+	r.log.Info().Str("name", name).Msg("deleting silence")
+	delete(r.silences, name)
+	return nil
+}
+
+// GetSilences returns all the silences, including the ones that are not active
+func (r *Runner) GetSilences() map[string]config.Silence {
+	r.RLock()
+	defer r.RUnlock()
+	silences := make(map[string]config.Silence, len(r.silences))
+	for name, silence := range r.silences {
+		silences[name] = silence
+	}
+	return silences
+}
+
+// silenceFor returns the active silence that matches the given check, if any,
+// silences that skip the execution take precedence
+func (r *Runner) silenceFor(check api.Check, t time.Time) (config.Silence, bool) {
+	checkType, name, _, err := check.Config()
+	if err != nil {
+		return config.Silence{}, false
+	}
+	labels := check.BaseConfig().Labels
+	r.RLock()
+	defer r.RUnlock()
+	var (
+		match config.Silence
+		found bool
+	)
+	for _, silence := range r.silences {
+		if !silence.Active(t) || !silence.Matches(name, checkType, labels) {
+			continue
+		}
+		match, found = silence, true
+		if silence.Mode == config.SilenceModeSkip {
+			break
+		}
+	}
+	return match, found
+}
+
 // GetStatus returns the overall status of all the checks
 func (r *Runner) GetStatus() api.Statuses {
 	r.RLock()
 	defer r.RUnlock()
-	return r.status
+	return maps.Clone(r.status)
 }
 
 // GetStatusFor returns the status for the given check
@@ -203,12 +520,43 @@ func (r *Runner) GetStatusFor(name string) (api.Status, bool) {
 	return n, ok
 }
 
-// updateStatusFor sets the status for the given check
-func (r *Runner) updateStatusFor(name string, status api.Status) {
+// updateStatusFor sets the status for the given check, when the executed check is given,
+// the status is dropped if the check was deleted or replaced by a different one while it was running
+func (r *Runner) updateStatusFor(name string, check api.Check, status api.Status) bool {
 	r.Lock()
+	if check != nil {
+		if cur, ok := r.checks[name]; !ok || (cur != check && !sameConfig(cur, check)) {
+			r.Unlock()
+			r.log.Info().Str("name", name).Msg("dropping the result of a check deleted or replaced while running")
+			return false
+		}
+	}
 	r.status[name] = status
+	if ch, ok := r.ready[name]; ok && status.Reason != api.ReasonPending {
+		close(ch)
+		delete(r.ready, name)
+	}
+	h, ok := r.history[name]
+	if !ok {
+		h = newHistory(r.historyCfg)
+		r.history[name] = h
+	}
+	var slo config.SLO
+	if cur, ok := r.checks[name]; ok {
+		slo = cur.BaseConfig().SLO
+	}
 	r.Unlock()
+	h.add(status)
+	if r.store != nil {
+		if err := r.store.Save(name, status); err != nil {
+			r.log.Err(err).Str("name", name).Msg("persisting check status")
+		}
+	}
 	r.updateMetricsFor(name)
+	if slo.Target > 0 {
+		r.updateSLOMetricsFor(name, status.Labels, h.slo(slo, time.Now()))
+	}
+	return true
 }
 
 // updateMetricsFor generates Prometheus metrics from the status of the given check
@@ -218,15 +566,47 @@ func (r *Runner) updateMetricsFor(name string) {
 		r.log.Warn().Str("name", name).Msg("status not found")
 		return
 	}
+	labels := r.metricLabelsFor(name, status.Labels)
+	if status.Skipped {
+		checkCount.With(withLabel(labels, "status", "skipped")).Inc()
+		return
+	}
 	var statusVal float64
-	statusName := "error"
 	if status.OK {
-		statusName = "success"
 		statusVal = 1
+		if status.Warning {
+			statusVal = 2
+		}
+	}
+	statusName := "error"
+	if status.LastOK {
+		statusName = "success"
+		if status.Warning {
+			statusName = "warning"
+		}
+	}
+	checkStatus.With(labels).Set(statusVal)
+	checkCount.With(withLabel(labels, "status", statusName)).Inc()
+	checkDuration.With(labels).Observe(float64(status.Duration.Milliseconds()))
+}
+
+// metricLabelsFor returns the Prometheus labels for the given check, including the configured check labels
+func (r *Runner) metricLabelsFor(name string, checkLabels map[string]string) prometheus.Labels {
+	labels := prometheus.Labels{"name": name}
+	for _, l := range r.metricLabels {
+		labels[config.MetricLabelName(l)] = checkLabels[l]
+	}
+	return labels
+}
+
+// withLabel returns a copy of the given labels with an extra label
+func withLabel(labels prometheus.Labels, key, value string) prometheus.Labels {
+	l := make(prometheus.Labels, len(labels)+1)
+	for k, v := range labels {
+		l[k] = v
 	}
-	checkStatus.With(prometheus.Labels{"name": name}).Set(statusVal)
-	checkCount.With(prometheus.Labels{"name": name, "status": statusName}).Inc()
-	checkDuration.With(prometheus.Labels{"name": name}).Observe(float64(status.Duration.Milliseconds()))
+	l[key] = value
+	return l
 }
 
 // Start schedules all the checks, running them periodically in the background, according to their configuration
@@ -239,6 +619,7 @@ func (r *Runner) Start() context.CancelFunc {
 // Run schedules all the checks, running them periodically in the background, according to their configuration
 // in the informer is configured, it will also set up a refresher to ensure the configuration is eventually consistent, even if we miss update events
 func (r *Runner) Run(ctx context.Context) {
+	r.started = true
 	for name := range r.checks {
 		if _, ok := r.stop[name]; ok {
 			// already running
@@ -281,23 +662,28 @@ func (r *Runner) upstreamRefresher(ctx context.Context) error {
 	return nil
 }
 
-// schedule executes the check on the configured interval
+// schedule executes the check on the configured interval or cron schedule
 func (r *Runner) schedule(ctx context.Context, name string) {
 	// ctx, _ = context.WithCancel(ctx)
 	r.log.Info().Str("name", name).Msg("starting checks")
+	// capture the check and stop channel, they're replaced when the check is updated
+	check, stop := r.checks[name], r.stop[name]
 	go func() {
-		time.Sleep(r.checks[name].InitialDelay().Duration)
-		r.check(ctx, name)
-		ticker := time.NewTicker(r.checks[name].Interval().Duration)
-		defer ticker.Stop()
+		time.Sleep(check.InitialDelay().Duration)
+		// the first execution waits for the checks it depends on, so that it doesn't fail just because they haven't run yet
+		r.waitForInputs(ctx, stop, name, check, check.Interval().Duration)
+		sched := newScheduler(check)
+		timer := time.NewTimer(sched.first(time.Now()))
+		defer timer.Stop()
 		for {
 			select {
-			case <-ticker.C:
-				r.check(ctx, name)
+			case <-timer.C:
+				timer.Reset(sched.next(time.Now()))
+				r.check(ctx, name, check)
 			case <-ctx.Done():
 				r.log.Info().Str("name", name).Msg("stopping checks")
 				return
-			case <-r.stop[name]:
+			case <-stop:
 				r.log.Info().Str("name", name).Msg("got quit signal stopping checks")
 				return
 			}
@@ -364,23 +750,185 @@ func (r *Runner) sync(leader string) error {
 		return err
 	}
 	for name, result := range status {
-		r.updateStatusFor(name, result)
+		r.updateStatusFor(name, nil, result)
 	}
 	return nil
 }
 
 // Check runs all the checks in parallel and waits for them to complete
 func (r *Runner) Check(ctx context.Context) {
-	var wg sync.WaitGroup
-	for name := range r.checks {
+	r.CheckSelected(ctx, Selector{})
+}
+
+// CheckSelected runs the checks matching the given selector in parallel and waits for them to complete,
+// the checks that depend on others are only executed after them
+func (r *Runner) CheckSelected(ctx context.Context, sel Selector) {
+	selected := make(api.Checks)
+	r.RLock()
+	for name, check := range r.checks {
+		if sel.Matches(check) {
+			selected[name] = check
+		}
+	}
+	r.RUnlock()
+	for _, stage := range executionOrder(selected) {
+		var wg sync.WaitGroup
+		for _, name := range stage {
+			wg.Add(1)
+			go func(name string, check api.Check) {
+				defer wg.Done()
+				time.Sleep(check.InitialDelay().Duration)
+				r.check(ctx, name, check)
+			}(name, selected[name])
+		}
+		wg.Wait()
+	}
+}
+
+// executionOrder splits the given checks into stages, where each check only depends on the checks in the previous stages,
+// the checks in a dependency cycle, and the ones depending on them, are left to the last stage
+func executionOrder(all api.Checks) [][]string {
+	inputs := make(map[string][]string, len(all))
+	for name, check := range all {
+		for _, input := range inputsOf(check) {
+			if _, ok := all[input]; ok && input != name {
+				inputs[name] = append(inputs[name], input)
+			}
+		}
+	}
+	var stages [][]string
+	done := make(map[string]bool, len(all))
+	for len(done) < len(all) {
+		var stage []string
+		for name := range all {
+			if !done[name] && !slices.ContainsFunc(inputs[name], func(input string) bool { return !done[input] }) {
+				stage = append(stage, name)
+			}
+		}
+		if len(stage) == 0 {
+			for name := range all {
+				if !done[name] {
+					stage = append(stage, name)
+				}
+			}
+		}
+		sort.Strings(stage)
+		for _, name := range stage {
+			done[name] = true
+		}
+		stages = append(stages, stage)
+	}
+	return stages
+}
+
+// Selector selects checks by type and labels, empty fields match any check
+type Selector struct {
+	// Types is a list of check types, e.g.: "http"
+	Types []string
+	// Labels selects the checks having all the given labels
+	Labels map[string]string
+}
+
+// ParseLabelSelector parses a comma separated list of labels in the key=value format, e.g.: "team=payments,tier=1"
+func ParseLabelSelector(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, l := range strings.Split(s, ",") {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(l, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label selector %q, must be in the key=value format", l)
+		}
+		labels[k] = v
+	}
+	return labels, nil
+}
+
+// Matches checks if the given check is selected
+func (s Selector) Matches(check api.Check) bool {
+	if len(s.Types) > 0 {
+		checkType, _, _, err := check.Config()
+		if err != nil || !slices.Contains(s.Types, checkType) {
+			return false
+		}
+	}
+	labels := check.BaseConfig().Labels
+	for k, v := range s.Labels {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Select returns the names of the checks matching the given selector
+func (r *Runner) Select(sel Selector) []string {
+	r.RLock()
+	defer r.RUnlock()
+	var names []string
+	for name, check := range r.checks {
+		if sel.Matches(check) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasCheck checks if the given check exists
+func (r *Runner) HasCheck(name string) bool {
+	r.RLock()
+	defer r.RUnlock()
+	_, ok := r.checks[name]
+	return ok
+}
+
+// RunCheck executes the given check right away, without changing its schedule, and returns the resulting status
+func (r *Runner) RunCheck(ctx context.Context, name string) (api.Status, error) {
+	r.RLock()
+	check, found := r.checks[name]
+	informOnly := r.informOnly
+	r.RUnlock()
+	if !found {
+		return api.Status{}, fmt.Errorf("%w: %s", ErrCheckNotFound, name)
+	}
+	if informOnly {
+		return api.Status{}, ErrInformOnly
+	}
+	r.log.Info().Str("name", name).Msg("running check on demand")
+	r.check(ctx, name, check)
+	status, _ := r.GetStatusFor(name)
+	return status, nil
+}
+
+// RunChecks executes the given checks in parallel, right away, without changing their schedule, and returns the resulting statuses
+func (r *Runner) RunChecks(ctx context.Context, names []string) (api.Statuses, error) {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses = make(api.Statuses, len(names))
+		errs     []error
+	)
+	for _, name := range names {
 		wg.Add(1)
-		go func(name string, check api.Check) {
+		go func(name string) {
 			defer wg.Done()
-			time.Sleep(check.InitialDelay().Duration)
-			r.check(ctx, name)
-		}(name, r.checks[name])
+			status, err := r.RunCheck(ctx, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			statuses[name] = status
+		}(name)
 	}
 	wg.Wait()
+	if len(errs) > 0 {
+		return statuses, errs[0]
+	}
+	return statuses, nil
 }
 
 func (r *Runner) Summary() (allFailed, anyFailed bool) {
@@ -388,25 +936,372 @@ func (r *Runner) Summary() (allFailed, anyFailed bool) {
 	return status.Evaluate()
 }
 
-// check executes one check and stores the resulting status
-func (r *Runner) check(ctx context.Context, name string) {
+// unknownDependencies returns the dependencies that don't match any check, by check name
+func (r *Runner) unknownDependencies() map[string][]string {
+	r.RLock()
+	defer r.RUnlock()
+	unknown := make(map[string][]string)
+	for name, check := range r.checks {
+		for _, dep := range check.BaseConfig().DependsOn {
+			if _, ok := r.checks[dep]; !ok {
+				unknown[name] = append(unknown[name], dep)
+			}
+		}
+	}
+	return unknown
+}
+
+// warnUnknownDependencies logs the dependencies that don't match any check,
+// they're ignored when deciding if a check should be skipped, so they're most likely a typo
+func (r *Runner) warnUnknownDependencies() {
+	for name, deps := range r.unknownDependencies() {
+		r.log.Warn().Str("name", name).Strs("dependsOn", deps).Msg("unknown dependencies, they will be ignored")
+	}
+}
+
+// blockedBy returns the dependencies, direct or transitive, of the given check that are currently failing
+func (r *Runner) blockedBy(name string) []string {
+	var failing []string
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		r.RLock()
+		check, ok := r.checks[queue[0]]
+		r.RUnlock()
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, dep := range check.BaseConfig().DependsOn {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			status, found := r.GetStatusFor(dep)
+			if !found {
+				// the dependency doesn't exist, the ones that haven't run yet are reported as pending
+				continue
+			}
+			if !status.OK && !status.Skipped {
+				failing = append(failing, dep)
+				continue
+			}
+			queue = append(queue, dep)
+		}
+	}
+	return failing
+}
+
+// inputsOf returns the names of the checks the given check depends on, including the ones combined by a composite check
+func inputsOf(check api.Check) []string {
+	inputs := check.BaseConfig().DependsOn
+	if c, ok := check.(checks.Composite); ok {
+		inputs = append(slices.Clone(inputs), c.Inputs()...)
+	}
+	return inputs
+}
+
+// dependsOn checks if the given check depends, directly or transitively, on the other one,
+// it must be called with the lock held
+func (r *Runner) dependsOn(name, other string) bool {
+	visited := map[string]bool{name: true}
+	queue := []string{name}
+	for len(queue) > 0 {
+		check, ok := r.checks[queue[0]]
+		queue = queue[1:]
+		if !ok {
+			continue
+		}
+		for _, input := range inputsOf(check) {
+			if input == other {
+				return true
+			}
+			if !visited[input] {
+				visited[input] = true
+				queue = append(queue, input)
+			}
+		}
+	}
+	return false
+}
+
+// pendingInputs returns the checks the given check depends on that haven't run yet,
+// unknown checks and the ones depending back on the given check are ignored, as they'd never run otherwise
+func (r *Runner) pendingInputs(name string, check api.Check) []string {
+	r.RLock()
+	defer r.RUnlock()
+	var pending []string
+	for _, input := range inputsOf(check) {
+		if _, ok := r.checks[input]; !ok {
+			continue
+		}
+		if status, ok := r.status[input]; ok && status.Reason != api.ReasonPending {
+			continue
+		}
+		if !r.dependsOn(input, name) {
+			pending = append(pending, input)
+		}
+	}
+	return pending
+}
+
+// statusReady returns a channel that's closed once the given check has run
+func (r *Runner) statusReady(name string) <-chan struct{} {
+	r.Lock()
+	defer r.Unlock()
+	if status, ok := r.status[name]; ok && status.Reason != api.ReasonPending {
+		ch := make(chan struct{})
+		close(ch)
+		return ch
+	}
+	ch, ok := r.ready[name]
+	if !ok {
+		ch = make(chan struct{})
+		r.ready[name] = ch
+	}
+	return ch
+}
+
+// waitForInputs waits, up to the given timeout, until the checks the given check depends on have run
+func (r *Runner) waitForInputs(ctx context.Context, stop <-chan struct{}, name string, check api.Check, timeout time.Duration) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		pending := r.pendingInputs(name, check)
+		if len(pending) == 0 {
+			return
+		}
+		select {
+		case <-r.statusReady(pending[0]):
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		}
+	}
+}
+
+// lockCheck waits until the given check is not being executed and returns a function to release it,
+// so that scheduled and on demand executions of the same check don't overlap
+func (r *Runner) lockCheck(name string) func() {
+	v, _ := r.running.LoadOrStore(name, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// check executes the given check and stores the resulting status,
+// the result is dropped if the check is deleted or replaced while it's running
+func (r *Runner) check(ctx context.Context, name string, check api.Check) {
+	defer r.lockCheck(name)()
 	var err error
-	status, _ := r.GetStatusFor(name)
+	status, found := r.GetStatusFor(name)
+	previous := status
 	status.Error = ""
+	status.Warning = false
 	status.Timestamp = time.Now()
-	status.OK, err = r.checks[name].Execute(ctx)
+	cfg := check.BaseConfig()
+	status.Labels = cfg.Labels
+	status.Owner = cfg.Owner
+	status.Severity = cfg.Severity
+	status.Critical = cfg.Critical
+	status.Description = cfg.Description
+	status.RunbookURL = cfg.RunbookURL
+	silence, silenced := r.silenceFor(check, status.Timestamp)
+	status.Silenced = silenced
+	if !isActive(cfg, status.Timestamp) {
+		status.Skipped = true
+		status.Reason = api.ReasonOutsideActiveWindow
+		r.log.Debug().Str("name", name).Msg("check skipped, outside of its active windows")
+		r.updateStatusFor(name, check, status)
+		return
+	}
+	if silenced && silence.Mode == config.SilenceModeSkip {
+		status.Skipped = true
+		status.Reason = api.ReasonSilenced
+		r.log.Debug().Str("name", name).Msg("check skipped, silenced")
+		r.updateStatusFor(name, check, status)
+		return
+	}
+	if pending := r.pendingInputs(name, check); len(pending) > 0 {
+		status.Skipped = true
+		status.Reason = api.ReasonPending
+		status.Error = fmt.Sprintf("waiting for checks that haven't run yet: %s", strings.Join(pending, ", "))
+		r.log.Debug().Str("name", name).Strs("pending", pending).Msg("check skipped")
+		r.updateStatusFor(name, check, status)
+		return
+	}
+	if blockers := r.blockedBy(name); len(blockers) > 0 {
+		status.Skipped = true
+		status.Reason = api.ReasonDependencyFailed
+		status.Error = fmt.Sprintf("blocked by failing dependencies: %s", strings.Join(blockers, ", "))
+		r.log.Warn().Str("name", name).Strs("blockedBy", blockers).Msg("check skipped")
+		r.updateStatusFor(name, check, status)
+		return
+	}
+	status.Skipped = false
+	status.Reason = ""
+	var (
+		duration time.Duration
+		attempts int
+	)
+	status.LastOK, attempts, duration, err = r.execute(ctx, name, check)
+	if attempts == 0 {
+		r.log.Err(err).Str("name", name).Msg("check cancelled while waiting to be executed")
+		return
+	}
+	status.Attempts = attempts
 	if err != nil {
 		status.Error = err.Error()
 	}
-	status.Duration = metav1.Duration{Duration: time.Since(status.Timestamp)}
-	if !status.OK {
+	status.Warning = status.LastOK && errors.Is(err, api.ErrWarning)
+	if status.LastOK && !status.Warning && cfg.WarnDuration.Duration > 0 && duration > cfg.WarnDuration.Duration {
+		status.Warning = true
+		status.Reason = api.ReasonDurationExceeded
+		status.Error = fmt.Sprintf("%s: the check took %s, more than the %s warning duration", api.ErrWarning, duration.Round(time.Millisecond), cfg.WarnDuration.Duration)
+	}
+	switch {
+	case errors.Is(err, ErrTimeout):
+		status.Reason = api.ReasonTimeout
+		checkTimeouts.With(r.metricLabelsFor(name, status.Labels)).Inc()
+	case errors.Is(err, ErrDurationExceeded):
+		status.Reason = api.ReasonDurationExceeded
+	}
+	if silenced && status.Reason == "" {
+		status.Reason = api.ReasonSilenced
+	}
+	status.Duration = metav1.Duration{Duration: duration}
+	if !status.LastOK {
 		if status.ContiguousFailures == 0 {
 			status.TimeOfFirstFailure = status.Timestamp
 		}
 		status.ContiguousFailures++
+		status.ContiguousSuccesses = 0
 	} else {
 		status.ContiguousFailures = 0
+		status.ContiguousSuccesses++
+	}
+	// a previous status holding only skips, e.g.: outside of the active windows, is the same as no previous status
+	executed := found && previous.ContiguousFailures+previous.ContiguousSuccesses > 0
+	status.OK = evalThresholds(status, cfg, executed)
+	r.log.Err(err).Bool("healthy", status.OK).Bool("lastOK", status.LastOK).Bool("warning", status.Warning).Bool("silenced", silenced).Str("name", name).Msg("check status")
+	if r.updateStatusFor(name, check, status) {
+		r.emitStatusEvents(name, previous, executed, status)
+	}
+}
+
+// execute runs the given check, retrying it on failure according to its configuration,
+// successful attempts taking longer than the maxDuration count as failures,
+// it returns the result and duration of the last attempt along with the number of attempts,
+// which is 0 if the context was cancelled while waiting for a concurrency slot for the first attempt
+func (r *Runner) execute(ctx context.Context, name string, check api.Check) (ok bool, attempts int, duration time.Duration, err error) {
+	cfg := check.BaseConfig()
+	delay := cfg.RetryDelay.Duration
+	if delay == 0 {
+		delay = time.Second
+	}
+	backoff := cfg.RetryBackoff
+	if backoff <= 0 {
+		backoff = 1
+	}
+	var target string
+	if t, ok := check.(api.Targeted); ok {
+		target = t.Target()
+	}
+	for {
+		// the concurrency slot is only held while the check is executing, not while waiting to retry it
+		release, acquireErr := r.limiter.acquire(ctx, target)
+		if acquireErr != nil {
+			if attempts == 0 {
+				err = acquireErr
+			}
+			return
+		}
+		attempts++
+		start := time.Now()
+		ok, err = executeWithTimeout(ctx, check, cfg.Timeout.Duration)
+		duration = time.Since(start)
+		release()
+		if ok && cfg.MaxDuration.Duration > 0 && duration > cfg.MaxDuration.Duration {
+			ok = false
+			err = fmt.Errorf("%w: the check took %s, more than the %s maximum duration", ErrDurationExceeded, duration.Round(time.Millisecond), cfg.MaxDuration.Duration)
+		}
+		if ok || attempts > cfg.Retries {
+			return
+		}
+		r.log.Err(err).Str("name", name).Int("attempt", attempts).Dur("retryIn", delay).Msg("check failed, retrying")
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = time.Duration(float64(delay) * backoff)
+	}
+}
+
+// executeWithTimeout runs the given check once, cancelling its context once the timeout expires,
+// checks that don't honour the context cancellation are abandoned so they don't block the runner
+func executeWithTimeout(ctx context.Context, check api.Check, timeout time.Duration) (bool, error) {
+	if timeout <= 0 {
+		return check.Execute(ctx)
+	}
+	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		ok  bool
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ok, err := check.Execute(execCtx)
+		done <- result{ok: ok, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-execCtx.Done():
+		res = result{err: execCtx.Err()}
+	}
+	// only report a timeout if the check itself timed out, not when the runner is being stopped
+	if !res.ok && ctx.Err() == nil && errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+		if res.err == nil {
+			res.err = execCtx.Err()
+		}
+		return false, fmt.Errorf("%w after %s: %v", ErrTimeout, timeout, res.err)
+	}
+	return res.ok, res.err
+}
+
+// sameConfig checks if both checks have the same type, name and configuration
+func sameConfig(a, b api.Check) bool {
+	aType, aName, aCfg, aErr := a.Config()
+	bType, bName, bCfg, bErr := b.Config()
+	return aErr == nil && bErr == nil && aType == bType && aName == bName && aCfg == bCfg
+}
+
+// evalThresholds returns the state to report for a check,
+// it only changes after the configured number of consecutive results
+// the first result of a check is always reported as is, skips don't count as results
+func evalThresholds(status api.Status, cfg config.BaseCheck, hasPrevious bool) bool {
+	if !hasPrevious {
+		return status.LastOK
+	}
+	failureThreshold, successThreshold := cfg.FailureThreshold, cfg.SuccessThreshold
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	if successThreshold < 1 {
+		successThreshold = 1
+	}
+	if status.OK && status.ContiguousFailures >= failureThreshold {
+		return false
+	}
+	if !status.OK && status.ContiguousSuccesses >= successThreshold {
+		return true
 	}
-	r.log.Err(err).Bool("healthy", status.OK).Str("name", name).Msg("check status")
-	r.updateStatusFor(name, status)
+	return status.OK
 }
@@ -2,11 +2,22 @@ package checker
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/jarcoal/httpmock"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"golang.org/x/exp/maps"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/luisdavim/synthetic-checker/pkg/api"
 	"github.com/luisdavim/synthetic-checker/pkg/config"
@@ -14,6 +25,12 @@ import (
 
 var checkName string = "test"
 
+func unregisterMetrics() {
+	for _, m := range []prometheus.Collector{checkCount, checkStatus, checkDuration, checkTimeouts, checkQueueLength, checkSchedulingDelay, checkSLOBudget, checkSLOBurnRate} {
+		prometheus.Unregister(m)
+	}
+}
+
 func TestChecker(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -108,15 +125,13 @@ func TestChecker(t *testing.T) {
 			c, err := NewFromConfig(tt.config, false)
 			defer func() {
 				// avoid panic with the prometheus.MustRegister used in NewFromConfig
-				prometheus.Unregister(checkCount)
-				prometheus.Unregister(checkStatus)
-				prometheus.Unregister(checkDuration)
+				unregisterMetrics()
 			}()
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
 			}
 			for name := range c.checks {
-				c.check(context.TODO(), name)
+				c.check(context.TODO(), name, c.checks[name])
 				actual, ok := c.GetStatusFor(name)
 				if !ok {
 					t.Errorf("missing status for %s", name)
@@ -270,9 +285,7 @@ func TestSync(t *testing.T) {
 			c, err := NewFromConfig(tt.config, false)
 			defer func() {
 				// avoid panic with the prometheus.MustRegister used in NewFromConfig
-				prometheus.Unregister(checkCount)
-				prometheus.Unregister(checkStatus)
-				prometheus.Unregister(checkDuration)
+				unregisterMetrics()
 			}()
 			if err != nil {
 				t.Errorf("unexpected error: %v", err)
@@ -299,3 +312,1047 @@ func TestSync(t *testing.T) {
 		})
 	}
 }
+
+func TestDependencies(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/parent", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/child", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/grandchild", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"parent": {
+				URL: "http://fake.com/parent",
+			},
+			"child": {
+				URL:       "http://fake.com/child",
+				BaseCheck: config.BaseCheck{DependsOn: []string{"parent-http"}},
+			},
+			"grandchild": {
+				URL:       "http://fake.com/grandchild",
+				BaseCheck: config.BaseCheck{DependsOn: []string{"child-http"}},
+			},
+			"typo": {
+				URL:       "http://fake.com/parent",
+				BaseCheck: config.BaseCheck{DependsOn: []string{"parent-http", "parnet-http"}},
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	unknown := c.unknownDependencies()
+	if len(unknown) != 1 || len(unknown["typo-http"]) != 1 || unknown["typo-http"][0] != "parnet-http" {
+		t.Errorf("unexpected unknown dependencies, wanted: map[typo-http:[parnet-http]], got: %v", unknown)
+	}
+
+	// dependencies that haven't run yet leave the check pending
+	c.check(context.TODO(), "child-http", c.checks["child-http"])
+	if status, _ := c.GetStatusFor("child-http"); !status.Skipped || status.Reason != api.ReasonPending {
+		t.Errorf("expected child-http to be pending, got: %+v", status)
+	}
+
+	// the dependencies are executed first
+	c.Check(context.TODO())
+	for _, name := range []string{"child-http", "grandchild-http"} {
+		status, _ := c.GetStatusFor(name)
+		if !status.Skipped || status.Reason != api.ReasonDependencyFailed {
+			t.Errorf("expected %s to be skipped, got: %+v", name, status)
+		}
+		if status.ContiguousFailures > 1 {
+			t.Errorf("unexpected number of contiguous failures for %s: %d", name, status.ContiguousFailures)
+		}
+	}
+	if allFailed, anyFailed := c.Summary(); allFailed != true || anyFailed != true {
+		t.Errorf("unexpected summary, wanted: true,true; got: %v,%v", allFailed, anyFailed)
+	}
+
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/parent", httpmock.NewStringResponder(http.StatusOK, ""))
+	c.check(context.TODO(), "parent-http", c.checks["parent-http"])
+	c.check(context.TODO(), "child-http", c.checks["child-http"])
+	if status, _ := c.GetStatusFor("child-http"); status.Skipped || status.Reason != "" {
+		t.Errorf("expected child-http to be executed, got: %+v", status)
+	}
+}
+
+func TestDependenciesScheduled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/parent", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/child", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"parent": {URL: "http://fake.com/parent"},
+			"child": {
+				URL:       "http://fake.com/child",
+				BaseCheck: config.BaseCheck{DependsOn: []string{"parent-http"}},
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failures := make(chan string, eventBufferSize)
+	unsubscribe := c.Subscribe(func(event api.Event) {
+		if event.Type == api.EventFirstFailure {
+			failures <- event.Name
+		}
+	})
+	defer unsubscribe()
+	stop := c.Start()
+	defer stop()
+	defer c.Stop()
+
+	// only the root cause is reported on the first round
+	deadline := time.Now().Add(time.Second)
+	for status, _ := c.GetStatusFor("child-http"); status.Reason != api.ReasonDependencyFailed && time.Now().Before(deadline); status, _ = c.GetStatusFor("child-http") {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.lockCheck("child-http")()
+	if status, _ := c.GetStatusFor("child-http"); !status.Skipped || status.Reason != api.ReasonDependencyFailed {
+		t.Errorf("expected child-http to be skipped, got: %+v", status)
+	}
+	select {
+	case name := <-failures:
+		if name != "parent-http" {
+			t.Errorf("unexpected failure for %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("the failure of parent-http was not reported")
+	}
+	select {
+	case name := <-failures:
+		t.Errorf("unexpected failure for %s", name)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCompositeInputsFirst(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/ok", httpmock.NewStringResponder(http.StatusOK, ""))
+	cfg := config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"input": {URL: "http://fake.com/ok"},
+		},
+		CompositeChecks: map[string]config.CompositeCheck{
+			"combined": {Checks: []string{"input-http"}},
+		},
+	}
+
+	t.Run("check", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			c, err := NewFromConfig(cfg, false)
+			if err != nil {
+				unregisterMetrics()
+				t.Fatalf("unexpected error: %v", err)
+			}
+			c.Check(context.TODO())
+			status, _ := c.GetStatusFor("combined-composite")
+			unregisterMetrics()
+			if !status.OK {
+				t.Fatalf("run %d: expected the composite to pass after its inputs, got: %+v", i, status)
+			}
+		}
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		c, err := NewFromConfig(cfg, false)
+		defer func() {
+			// avoid panic with the prometheus.MustRegister used in NewFromConfig
+			unregisterMetrics()
+		}()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		failures := make(chan api.Event, eventBufferSize)
+		unsubscribe := c.Subscribe(func(event api.Event) {
+			if event.Type == api.EventFirstFailure || event.Type == api.EventStatusChanged {
+				failures <- event
+			}
+		})
+		defer unsubscribe()
+		stop := c.Start()
+		defer stop()
+		defer c.Stop()
+
+		deadline := time.Now().Add(time.Second)
+		for status, _ := c.GetStatusFor("combined-composite"); !status.OK && time.Now().Before(deadline); status, _ = c.GetStatusFor("combined-composite") {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if status, _ := c.GetStatusFor("combined-composite"); !status.OK {
+			t.Errorf("expected the composite to pass after its inputs, got: %+v", status)
+		}
+		select {
+		case event := <-failures:
+			t.Errorf("unexpected %s event for %s: %+v", event.Type, event.Name, event.Status)
+		case <-time.After(50 * time.Millisecond):
+		}
+		// wait for the execution to complete, the metrics are updated after the status
+		c.lockCheck("combined-composite")()
+	})
+
+	t.Run("on demand", func(t *testing.T) {
+		c, err := NewFromConfig(cfg, false)
+		defer func() {
+			// avoid panic with the prometheus.MustRegister used in NewFromConfig
+			unregisterMetrics()
+		}()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// the input hasn't run yet
+		status, err := c.RunCheck(context.TODO(), "combined-composite")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !status.Skipped || status.Reason != api.ReasonPending {
+			t.Errorf("expected the composite to be pending, got: %+v", status)
+		}
+		if allFailed, anyFailed := c.Summary(); allFailed || anyFailed {
+			t.Errorf("unexpected summary, wanted: false,false; got: %v,%v", allFailed, anyFailed)
+		}
+	})
+}
+
+// dependentCheck is a stub check that depends on the given checks
+type dependentCheck struct {
+	stubCheck
+	inputs []string
+}
+
+func (c *dependentCheck) Inputs() []string { return c.inputs }
+
+func TestExecutionOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   api.Checks
+		expected [][]string
+	}{
+		{
+			name: "independent",
+			checks: api.Checks{
+				"a": &stubCheck{},
+				"b": &stubCheck{},
+			},
+			expected: [][]string{{"a", "b"}},
+		},
+		{
+			name: "chain",
+			checks: api.Checks{
+				"a": &dependentCheck{inputs: []string{"b"}},
+				"b": &dependentCheck{inputs: []string{"c", "unknown"}},
+				"c": &stubCheck{},
+				"d": &stubCheck{},
+			},
+			expected: [][]string{{"c", "d"}, {"b"}, {"a"}},
+		},
+		{
+			name: "cycle",
+			checks: api.Checks{
+				"a": &dependentCheck{inputs: []string{"b"}},
+				"b": &dependentCheck{inputs: []string{"a"}},
+				"c": &stubCheck{},
+			},
+			expected: [][]string{{"c"}, {"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stages := executionOrder(tt.checks)
+			if !cmp.Equal(stages, tt.expected) {
+				t.Errorf("unexpected stages, wanted: %v, got: %v", tt.expected, stages)
+			}
+		})
+	}
+}
+
+func TestThresholds(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			checkName: {
+				URL: "http://fake.com/flaky",
+				BaseCheck: config.BaseCheck{
+					FailureThreshold: 3,
+					SuccessThreshold: 2,
+				},
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	steps := []struct {
+		statusCode int
+		ok         bool
+		lastOK     bool
+	}{
+		{statusCode: http.StatusOK, ok: true, lastOK: true},
+		{statusCode: http.StatusInternalServerError, ok: true, lastOK: false},
+		{statusCode: http.StatusInternalServerError, ok: true, lastOK: false},
+		{statusCode: http.StatusInternalServerError, ok: false, lastOK: false},
+		{statusCode: http.StatusOK, ok: false, lastOK: true},
+		{statusCode: http.StatusInternalServerError, ok: false, lastOK: false},
+		{statusCode: http.StatusOK, ok: false, lastOK: true},
+		{statusCode: http.StatusOK, ok: true, lastOK: true},
+	}
+	for i, step := range steps {
+		httpmock.RegisterResponder(http.MethodGet, "http://fake.com/flaky", httpmock.NewStringResponder(step.statusCode, ""))
+		c.check(context.TODO(), checkName+"-http", c.checks[checkName+"-http"])
+		status, _ := c.GetStatusFor(checkName + "-http")
+		if status.OK != step.ok || status.LastOK != step.lastOK {
+			t.Errorf("step %d: unexpected status, wanted: %t,%t; got: %t,%t", i, step.ok, step.lastOK, status.OK, status.LastOK)
+		}
+	}
+}
+
+func TestThresholdsAfterSkips(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := make(chan api.Event, eventBufferSize)
+	unsubscribe := c.Subscribe(func(event api.Event) {
+		if event.Type == api.EventStatusChanged {
+			events <- event
+		}
+	})
+	defer unsubscribe()
+
+	parent := &stubCheck{ok: false}
+	child := &stubCheck{ok: true, cfg: config.BaseCheck{DependsOn: []string{"parent"}, SuccessThreshold: 3}}
+	c.AddCheck("parent", parent, false)
+	c.AddCheck("child", child, false)
+	c.check(context.TODO(), "parent", parent)
+	c.check(context.TODO(), "child", child)
+	if status, _ := c.GetStatusFor("child"); !status.Skipped {
+		t.Fatalf("expected the child check to be skipped, got: %+v", status)
+	}
+
+	// the first execution after the skips is reported as is, the check never failed
+	parent.ok = true
+	c.check(context.TODO(), "parent", parent)
+	c.check(context.TODO(), "child", child)
+	if status, _ := c.GetStatusFor("child"); !status.OK || status.Skipped {
+		t.Errorf("expected the child check to be OK, got: %+v", status)
+	}
+	// the events are delivered from another goroutine
+	timeout := time.After(100 * time.Millisecond)
+	for done := false; !done; {
+		select {
+		case event := <-events:
+			if event.Name == "child" {
+				t.Errorf("unexpected status change for the child check: %+v", event.Status)
+			}
+		case <-timeout:
+			done = true
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	calls := 0
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/retry", func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, ""), nil
+	})
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"ok": {
+				URL: "http://fake.com/retry",
+				BaseCheck: config.BaseCheck{
+					Retries:      2,
+					RetryDelay:   metav1.Duration{Duration: time.Millisecond},
+					RetryBackoff: 2,
+				},
+			},
+			"ko": {
+				URL: "http://fake.com/retry",
+				BaseCheck: config.BaseCheck{
+					Retries:    1,
+					RetryDelay: metav1.Duration{Duration: time.Millisecond},
+				},
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.check(context.TODO(), "ok-http", c.checks["ok-http"])
+	status, _ := c.GetStatusFor("ok-http")
+	if !status.OK || status.Attempts != 3 || status.ContiguousFailures != 0 {
+		t.Errorf("unexpected status, wanted OK after 3 attempts, got: %+v", status)
+	}
+
+	calls = 0
+	c.check(context.TODO(), "ko-http", c.checks["ko-http"])
+	status, _ = c.GetStatusFor("ko-http")
+	if status.OK || status.Attempts != 2 || status.ContiguousFailures != 1 {
+		t.Errorf("unexpected status, wanted a failure after 2 attempts, got: %+v", status)
+	}
+}
+
+// hangingCheck blocks until released, ignoring the context cancellation
+type hangingCheck struct {
+	release chan struct{}
+}
+
+func (c *hangingCheck) Config() (string, string, string, error) {
+	return "hanging", "hanging", "{}", nil
+}
+func (c *hangingCheck) Interval() metav1.Duration     { return metav1.Duration{Duration: time.Minute} }
+func (c *hangingCheck) InitialDelay() metav1.Duration { return metav1.Duration{} }
+func (c *hangingCheck) BaseConfig() config.BaseCheck {
+	return config.BaseCheck{Timeout: metav1.Duration{Duration: 10 * time.Millisecond}}
+}
+
+func (c *hangingCheck) Execute(ctx context.Context) (bool, error) {
+	<-c.release
+	return true, nil
+}
+
+func TestTimeout(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := &hangingCheck{release: make(chan struct{})}
+	defer close(check.release)
+	c.AddCheck(checkName, check, false)
+
+	done := make(chan struct{})
+	go func() {
+		c.check(context.TODO(), checkName, c.checks[checkName])
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the runner was blocked by a hanging check")
+	}
+
+	status, _ := c.GetStatusFor(checkName)
+	if status.OK || status.Reason != api.ReasonTimeout {
+		t.Errorf("unexpected status, wanted a timeout, got: %+v", status)
+	}
+}
+
+// stubCheck returns the given result after the given delay
+type stubCheck struct {
+	cfg   config.BaseCheck
+	ok    bool
+	err   error
+	delay time.Duration
+}
+
+func (c *stubCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.cfg)
+	return "stub", "stub", string(b), err
+}
+func (c *stubCheck) Interval() metav1.Duration     { return metav1.Duration{Duration: time.Minute} }
+func (c *stubCheck) InitialDelay() metav1.Duration { return metav1.Duration{} }
+func (c *stubCheck) BaseConfig() config.BaseCheck  { return c.cfg }
+
+func (c *stubCheck) Execute(ctx context.Context) (bool, error) {
+	time.Sleep(c.delay)
+	return c.ok, c.err
+}
+
+func TestWarnings(t *testing.T) {
+	type expected struct {
+		ok      bool
+		warning bool
+		metric  float64
+	}
+	tests := []struct {
+		name     string
+		check    *stubCheck
+		expected expected
+	}{
+		{
+			name:     "OK",
+			check:    &stubCheck{ok: true},
+			expected: expected{ok: true, metric: 1},
+		},
+		{
+			name:     "warning",
+			check:    &stubCheck{ok: true, err: fmt.Errorf("%w: almost failing", api.ErrWarning)},
+			expected: expected{ok: true, warning: true, metric: 2},
+		},
+		{
+			name:     "failed with a warning",
+			check:    &stubCheck{ok: false, err: fmt.Errorf("%w: failing", api.ErrWarning)},
+			expected: expected{ok: false, metric: 0},
+		},
+		{
+			name: "slow",
+			check: &stubCheck{
+				ok:    true,
+				delay: 10 * time.Millisecond,
+				cfg:   config.BaseCheck{WarnDuration: metav1.Duration{Duration: time.Millisecond}},
+			},
+			expected: expected{ok: true, warning: true, metric: 2},
+		},
+		{
+			name: "too slow",
+			check: &stubCheck{
+				ok:    true,
+				delay: 10 * time.Millisecond,
+				cfg:   config.BaseCheck{MaxDuration: metav1.Duration{Duration: time.Millisecond}},
+			},
+			expected: expected{ok: false, metric: 0},
+		},
+	}
+
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.AddCheck(checkName, tt.check, false)
+			defer c.DelCheck(checkName)
+			c.check(context.TODO(), checkName, c.checks[checkName])
+
+			status, _ := c.GetStatusFor(checkName)
+			if status.OK != tt.expected.ok {
+				t.Errorf("unexpected status, wanted: %t, got: %t", tt.expected.ok, status.OK)
+			}
+			if status.Warning != tt.expected.warning {
+				t.Errorf("unexpected warning, wanted: %t, got: %t (%s)", tt.expected.warning, status.Warning, status.Error)
+			}
+			if tt.expected.warning && status.Error == "" {
+				t.Errorf("the warning was not recorded")
+			}
+			if tt.check.cfg.MaxDuration.Duration+tt.check.cfg.WarnDuration.Duration > 0 && status.Reason != api.ReasonDurationExceeded {
+				t.Errorf("unexpected reason, wanted: %s, got: %s", api.ReasonDurationExceeded, status.Reason)
+			}
+			if v := testutil.ToFloat64(checkStatus.WithLabelValues(checkName)); v != tt.expected.metric {
+				t.Errorf("unexpected metric value, wanted: %v, got: %v", tt.expected.metric, v)
+			}
+		})
+	}
+}
+
+// overlapCheck records how many of its executions run at the same time
+type overlapCheck struct {
+	stubCheck
+	running int32
+	max     int32
+}
+
+func (c *overlapCheck) Execute(ctx context.Context) (bool, error) {
+	n := atomic.AddInt32(&c.running, 1)
+	defer atomic.AddInt32(&c.running, -1)
+	for {
+		max := atomic.LoadInt32(&c.max)
+		if n <= max || atomic.CompareAndSwapInt32(&c.max, max, n) {
+			break
+		}
+	}
+	return c.stubCheck.Execute(ctx)
+}
+
+func TestRunCheckNoOverlap(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := &overlapCheck{stubCheck: stubCheck{ok: true, delay: 10 * time.Millisecond}}
+	c.AddCheck(checkName, check, false)
+	defer c.DelCheck(checkName)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.check(context.TODO(), checkName, check)
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := c.RunCheck(context.TODO(), checkName); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := atomic.LoadInt32(&check.max); max != 1 {
+		t.Errorf("unexpected concurrent executions, wanted: 1, got: %d", max)
+	}
+}
+
+func TestDropStaleResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Runner)
+		found  bool
+	}{
+		{
+			name:   "deleted",
+			change: func(c *Runner) { c.DelCheck(checkName) },
+		},
+		{
+			name: "replaced",
+			change: func(c *Runner) {
+				c.AddCheck(checkName, &stubCheck{ok: true, cfg: config.BaseCheck{FailureThreshold: 2}}, false)
+			},
+		},
+		{
+			name:   "re-added unchanged",
+			change: func(c *Runner) { c.AddCheck(checkName, &stubCheck{ok: true}, false) },
+			found:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewFromConfig(config.Config{}, false)
+			defer func() {
+				// avoid panic with the prometheus.MustRegister used in NewFromConfig
+				unregisterMetrics()
+			}()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			check := &stubCheck{ok: false, delay: 50 * time.Millisecond}
+			c.AddCheck(checkName, check, false)
+			defer c.DelCheck(checkName)
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				c.check(context.TODO(), checkName, check)
+			}()
+			time.Sleep(10 * time.Millisecond)
+			tt.change(c)
+			<-done
+
+			status, found := c.GetStatus()[checkName]
+			if found != tt.found {
+				t.Fatalf("unexpected status presence, wanted: %t, got: %t (%+v)", tt.found, found, status)
+			}
+			if !tt.found {
+				if _, anyFailed := c.Summary(); anyFailed {
+					t.Errorf("the result of a stale check shouldn't be reported")
+				}
+			}
+		})
+	}
+}
+
+func TestHeartbeatDeadline(t *testing.T) {
+	c, err := NewFromConfig(config.Config{
+		HeartbeatChecks: map[string]config.HeartbeatCheck{
+			"job": {
+				GracePeriod: metav1.Duration{Duration: 20 * time.Millisecond},
+				BaseCheck:   config.BaseCheck{Interval: metav1.Duration{Duration: 200 * time.Millisecond}},
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stop := c.Start()
+	defer stop()
+	defer c.Stop()
+
+	// the ping is missed after 220ms, the failure must be reported within the grace period, not at the next interval
+	time.Sleep(300 * time.Millisecond)
+	status, _ := c.GetStatusFor("job-heartbeat")
+	if status.OK {
+		t.Errorf("expected the missed ping to be reported, got: %+v", status)
+	}
+}
+
+func TestSilences(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/ko", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"payments": {
+				URL: "http://fake.com/ko",
+				BaseCheck: config.BaseCheck{
+					Labels: map[string]string{"team": "payments"},
+				},
+			},
+			"other": {
+				URL: "http://fake.com/ko",
+			},
+		},
+		Silences: map[string]config.Silence{
+			"maintenance": {
+				Labels: map[string]string{"team": "payments"},
+				EndsAt: time.Now().Add(time.Hour),
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.check(context.TODO(), "payments-http", c.checks["payments-http"])
+	c.check(context.TODO(), "other-http", c.checks["other-http"])
+	status, _ := c.GetStatusFor("payments-http")
+	if !status.Silenced || status.Skipped || status.Attempts != 1 || status.Reason != api.ReasonSilenced {
+		t.Errorf("unexpected status, wanted a silenced execution, got: %+v", status)
+	}
+	status, _ = c.GetStatusFor("other-http")
+	if status.Silenced {
+		t.Errorf("unexpected status, wanted a non silenced check, got: %+v", status)
+	}
+
+	if err := c.AddSilence("skip", config.Silence{Types: []string{"http"}, Mode: config.SilenceModeSkip}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.check(context.TODO(), "other-http", c.checks["other-http"])
+	status, _ = c.GetStatusFor("other-http")
+	if !status.Silenced || !status.Skipped || status.Reason != api.ReasonSilenced {
+		t.Errorf("unexpected status, wanted a skipped check, got: %+v", status)
+	}
+	if allFailed, anyFailed := c.GetStatus().Evaluate(); allFailed || anyFailed {
+		t.Errorf("unexpected evaluation, silenced checks should be ignored, got: %t, %t", allFailed, anyFailed)
+	}
+
+	if err := c.DelSilence("skip"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.DelSilence("skip"); !errors.Is(err, ErrSilenceNotFound) {
+		t.Errorf("unexpected error, wanted: %v, got: %v", ErrSilenceNotFound, err)
+	}
+	if err := c.AddSilence("invalid", config.Silence{}); err == nil {
+		t.Errorf("expected an error for a silence without matchers")
+	}
+}
+
+func TestStore(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/ko", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	alerts := make(chan string, 100)
+	httpmock.RegisterResponder(http.MethodPost, "http://alertmanager.fake.com/api/v2/alerts", func(req *http.Request) (*http.Response, error) {
+		var body []struct {
+			Labels map[string]string `json:"labels"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		for _, al := range body {
+			alerts <- al.Labels["alertname"]
+		}
+		return httpmock.NewStringResponse(http.StatusOK, ""), nil
+	})
+
+	cfg := config.Config{
+		Store: config.StoreCfg{Path: filepath.Join(t.TempDir(), "state.db")},
+		Notifiers: config.NotifiersCfg{
+			Alertmanagers: map[string]config.AlertmanagerNotifier{
+				"default": {URL: "http://alertmanager.fake.com", ResendInterval: metav1.Duration{Duration: 10 * time.Millisecond}},
+			},
+		},
+	}
+	c, err := NewFromConfig(cfg, false)
+	if err != nil {
+		unregisterMetrics()
+		t.Fatalf("unexpected error: %v", err)
+	}
+	added := config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"added": {URL: "http://fake.com/ko"},
+		},
+	}
+	if err := c.AddFromConfig(added, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Persist("added-http", added); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.check(context.TODO(), "added-http", c.checks["added-http"])
+	c.check(context.TODO(), "added-http", c.checks["added-http"])
+	select {
+	case <-alerts:
+	case <-time.After(time.Second):
+		t.Fatalf("the alert of the failing check was not sent")
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unregisterMetrics()
+
+	// a new runner, e.g.: after a restart, restores the check, its status and history
+	c, err = NewFromConfig(cfg, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer c.Close()
+	if _, ok := c.checks["added-http"]; !ok {
+		t.Fatalf("the check added at runtime was not restored")
+	}
+	status, _ := c.GetStatusFor("added-http")
+	if status.ContiguousFailures != 2 {
+		t.Errorf("unexpected number of contiguous failures, wanted: 2, got: %d", status.ContiguousFailures)
+	}
+	history, _ := c.GetHistoryFor("added-http")
+	if len(history.Results) != 2 {
+		t.Errorf("unexpected history, wanted 2 results, got: %d", len(history.Results))
+	}
+
+	// the alert of the restored failing check is still re-sent
+	select {
+	case name := <-alerts:
+		if name != "added-http" {
+			t.Errorf("unexpected alert, wanted: added-http, got: %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("the alert of the restored failing check was not re-sent")
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/ok", httpmock.NewStringResponder(http.StatusOK, ""))
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"payments": {
+				URL: "http://fake.com/ok",
+				BaseCheck: config.BaseCheck{
+					Labels: map[string]string{"team": "payments"},
+				},
+			},
+			"other": {
+				URL: "http://fake.com/ok",
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := c.Select(Selector{Types: []string{"http"}, Labels: map[string]string{"team": "payments"}})
+	if len(names) != 1 || names[0] != "payments-http" {
+		t.Fatalf("unexpected selection, wanted: [payments-http], got: %v", names)
+	}
+	if names := c.Select(Selector{Types: []string{"dns"}}); len(names) != 0 {
+		t.Errorf("unexpected selection, wanted no checks, got: %v", names)
+	}
+
+	statuses, err := c.RunChecks(context.TODO(), names)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := statuses["payments-http"]; !status.OK || status.Timestamp.IsZero() {
+		t.Errorf("unexpected status, wanted: true, got: %+v", status)
+	}
+	if _, ok := c.GetStatusFor("other-http"); ok {
+		t.Errorf("the check that wasn't selected was executed")
+	}
+
+	if _, err := c.RunCheck(context.TODO(), "missing-http"); !errors.Is(err, ErrCheckNotFound) {
+		t.Errorf("unexpected error, wanted: %v, got: %v", ErrCheckNotFound, err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, "http://fake.com/ok", httpmock.NewStringResponder(http.StatusOK, ""))
+
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"payments": {
+				URL: "http://fake.com/ok",
+				BaseCheck: config.BaseCheck{
+					Labels:     map[string]string{"team": "payments", "app.kubernetes.io/name": "api"},
+					Owner:      "payments-team",
+					Severity:   "critical",
+					Critical:   true,
+					RunbookURL: "https://runbooks.example.com/payments",
+				},
+			},
+			"other": {
+				URL: "http://fake.com/ok",
+			},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.CheckSelected(context.TODO(), Selector{Labels: map[string]string{"team": "payments"}})
+
+	status, ok := c.GetStatusFor("payments-http")
+	if !ok {
+		t.Fatalf("the selected check was not executed")
+	}
+	if status.Owner != "payments-team" || status.Severity != "critical" || !status.Critical || status.RunbookURL == "" || status.Labels["team"] != "payments" {
+		t.Errorf("unexpected status metadata, got: %+v", status)
+	}
+	if _, ok := c.GetStatusFor("other-http"); ok {
+		t.Errorf("the check that wasn't selected was executed")
+	}
+}
+
+func TestMetricLabels(t *testing.T) {
+	// the metrics are not registered, the default registry doesn't allow changing the label names of a metric once registered
+	initMetrics([]string{"team", "app.kubernetes.io/name"})
+	r := &Runner{metricLabels: []string{"team", "app.kubernetes.io/name"}}
+
+	labels := r.metricLabelsFor("payments-http", map[string]string{"team": "payments", "app.kubernetes.io/name": "api"})
+	expected := prometheus.Labels{"name": "payments-http", "team": "payments", "app_kubernetes_io_name": "api"}
+	if !maps.Equal(labels, expected) {
+		t.Errorf("unexpected labels, wanted: %v, got: %v", expected, labels)
+	}
+	checkStatus.With(labels).Set(1)
+	if up := testutil.ToFloat64(checkStatus.With(expected)); up != 1 {
+		t.Errorf("unexpected metric value, wanted: 1, got: %v", up)
+	}
+
+	// checks without the configured labels get empty values
+	labels = r.metricLabelsFor("other-http", nil)
+	if labels["team"] != "" || len(labels) != 3 {
+		t.Errorf("unexpected labels, got: %v", labels)
+	}
+}
+
+func TestInvalidMetricLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+	}{
+		{name: "built-in name", labels: []string{"name"}},
+		{name: "built-in status", labels: []string{"team", "status"}},
+		{name: "built-in window", labels: []string{"window"}},
+		{name: "histogram bucket", labels: []string{"le"}},
+		{name: "clash after conversion", labels: []string{"a.b", "a_b"}},
+		{name: "duplicate", labels: []string{"team", "team"}},
+		{name: "reserved prefix", labels: []string{"__team"}},
+		{name: "leading digit", labels: []string{"1team"}},
+		{name: "empty", labels: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the config is rejected before the metrics are registered
+			if _, err := NewFromConfig(config.Config{Metrics: config.MetricsCfg{Labels: tt.labels}}, false); err == nil {
+				t.Errorf("expected an error for the metric labels %q", tt.labels)
+			}
+		})
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			name:     "empty",
+			selector: "",
+			expected: map[string]string{},
+		},
+		{
+			name:     "multiple labels",
+			selector: "team=payments, tier=1",
+			expected: map[string]string{"team": "payments", "tier": "1"},
+		},
+		{
+			name:     "empty value",
+			selector: "team=",
+			expected: map[string]string{"team": ""},
+		},
+		{
+			name:     "missing value",
+			selector: "team",
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, err := ParseLabelSelector(tt.selector)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error, wanted: %t, got: %v", tt.err, err)
+			}
+			if !tt.err && !maps.Equal(labels, tt.expected) {
+				t.Errorf("unexpected labels, wanted: %v, got: %v", tt.expected, labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+)
+
+// eventBufferSize is the number of events buffered for each subscriber before dropping them
+const eventBufferSize = 100
+
+// eventBus delivers the runner events to its subscribers without blocking the runner
+type eventBus struct {
+	subscribers map[int]chan api.Event
+	next        int
+	closed      bool
+	log         zerolog.Logger
+	sync.RWMutex
+}
+
+func newEventBus(log zerolog.Logger) *eventBus {
+	return &eventBus{
+		subscribers: make(map[int]chan api.Event),
+		log:         log,
+	}
+}
+
+// subscribe returns a channel that receives the published events and a function to close it,
+// the channel is also closed when the bus is closed
+func (b *eventBus) subscribe() (<-chan api.Event, func()) {
+	b.Lock()
+	defer b.Unlock()
+	events := make(chan api.Event, eventBufferSize)
+	if b.closed {
+		close(events)
+		return events, func() {}
+	}
+	id := b.next
+	b.next++
+	b.subscribers[id] = events
+	return events, func() {
+		b.Lock()
+		defer b.Unlock()
+		if ch, ok := b.subscribers[id]; ok {
+			delete(b.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// publish sends the given event to all the subscribers, dropping it for the ones that can't keep up
+func (b *eventBus) publish(event api.Event) {
+	b.RLock()
+	defer b.RUnlock()
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.log.Warn().Str("name", event.Name).Str("event", string(event.Type)).Msg("event dropped, subscriber is not keeping up")
+		}
+	}
+}
+
+// close closes all the subscriptions
+func (b *eventBus) close() {
+	b.Lock()
+	defer b.Unlock()
+	for id, ch := range b.subscribers {
+		delete(b.subscribers, id)
+		close(ch)
+	}
+	b.closed = true
+}
+
+// Subscribe registers a callback that receives the runner events, in order, from a dedicated goroutine,
+// events are dropped if the callback doesn't keep up. The returned function removes the subscription.
+func (r *Runner) Subscribe(callback func(api.Event)) (unsubscribe func()) {
+	events, unsubscribe := r.events.subscribe()
+	go func() {
+		for event := range events {
+			callback(event)
+		}
+	}()
+	return unsubscribe
+}
+
+// Events returns a channel that receives the runner events until the given context is done or the runner is closed,
+// events are dropped if the receiver doesn't keep up.
+func (r *Runner) Events(ctx context.Context) <-chan api.Event {
+	events, unsubscribe := r.events.subscribe()
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+	return events
+}
+
+// emitCheckEvent publishes an event about the configuration of the given check
+func (r *Runner) emitCheckEvent(eventType api.EventType, name string) {
+	r.events.publish(api.Event{
+		Type:      eventType,
+		Name:      name,
+		Timestamp: time.Now(),
+	})
+}
+
+// emitStatusEvents publishes the events for the transitions between the previous and the current status of the given check,
+// the status is published again when a silence ends, as the notifiers ignore most of the events of silenced checks
+func (r *Runner) emitStatusEvents(name string, previous api.Status, found bool, status api.Status) {
+	event := func(eventType api.EventType) api.Event {
+		return api.Event{
+			Type:      eventType,
+			Name:      name,
+			Timestamp: status.Timestamp,
+			Status:    &status,
+		}
+	}
+	if status.OK != (previous.OK || !found) || (found && previous.Silenced && !status.Silenced) {
+		r.events.publish(event(api.EventStatusChanged))
+	}
+	if !status.LastOK && status.ContiguousFailures == 1 {
+		r.events.publish(event(api.EventFirstFailure))
+	}
+	if status.LastOK && status.ContiguousSuccesses == 1 && previous.ContiguousFailures > 0 {
+		recovered := event(api.EventRecovered)
+		recovered.Failures = previous.ContiguousFailures
+		r.events.publish(recovered)
+	}
+}
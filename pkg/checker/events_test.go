@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestEvents(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var received []api.Event
+	done := make(chan struct{})
+	events := c.Events(context.Background())
+	go func() {
+		for event := range events {
+			received = append(received, event)
+		}
+		close(done)
+	}()
+
+	callbacks := make(chan api.Event, eventBufferSize)
+	unsubscribe := c.Subscribe(func(event api.Event) { callbacks <- event })
+
+	check := &stubCheck{ok: true}
+	c.AddCheck(checkName, check, false)
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	check.ok = false
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	check.ok = true
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	c.AddCheck(checkName, &stubCheck{ok: true, cfg: config.BaseCheck{Owner: "someone"}}, false)
+	c.DelCheck(checkName)
+
+	unsubscribe()
+	if err := c.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the event stream was not closed")
+	}
+
+	expected := []api.EventType{
+		api.EventCheckAdded,
+		api.EventStatusChanged,
+		api.EventFirstFailure,
+		api.EventStatusChanged,
+		api.EventRecovered,
+		api.EventCheckUpdated,
+		api.EventCheckDeleted,
+	}
+	if len(received) != len(expected) {
+		t.Fatalf("unexpected events, wanted: %v, got: %+v", expected, received)
+	}
+	for i, event := range received {
+		if event.Type != expected[i] || event.Name != checkName {
+			t.Errorf("unexpected event %d, wanted: %s, got: %s for %s", i, expected[i], event.Type, event.Name)
+		}
+	}
+	if received[1].Status == nil || received[1].Status.OK {
+		t.Errorf("the status change should report the failed status, got: %+v", received[1].Status)
+	}
+	if received[4].Failures != 2 {
+		t.Errorf("unexpected number of failures, wanted: 2, got: %d", received[4].Failures)
+	}
+	// the callbacks are called from another goroutine
+	for deadline := time.Now().Add(time.Second); len(callbacks) < len(expected) && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if len(callbacks) != len(expected) {
+		t.Errorf("unexpected number of callbacks, wanted: %d, got: %d", len(expected), len(callbacks))
+	}
+}
+
+func TestSilencedEvents(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := make(chan api.Event, eventBufferSize)
+	unsubscribe := c.Subscribe(func(event api.Event) {
+		if event.Type == api.EventStatusChanged {
+			events <- event
+		}
+	})
+	defer unsubscribe()
+
+	check := &stubCheck{ok: true}
+	c.AddCheck(checkName, check, false)
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	if err := c.AddSilence("maintenance", config.Silence{Types: []string{"stub"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	check.ok = false
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	if err := c.DelSilence("maintenance"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the status didn't change but the silence ended
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	c.check(context.TODO(), checkName, c.checks[checkName])
+
+	expected := []struct{ ok, silenced bool }{
+		{ok: false, silenced: true},
+		{ok: false},
+	}
+	for i, e := range expected {
+		select {
+		case event := <-events:
+			if event.Status.OK != e.ok || event.Status.Silenced != e.silenced {
+				t.Errorf("unexpected status change %d, wanted: %+v, got: ok: %t, silenced: %t", i, e, event.Status.OK, event.Status.Silenced)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing status change %d, wanted: %+v", i, e)
+		}
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected status change: %+v", event.Status)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
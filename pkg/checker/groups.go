@@ -0,0 +1,105 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// MatchesStatus checks if the given status is selected,
+// statuses are used instead of checks so that it also works when syncing the statuses from a leader
+func (s Selector) MatchesStatus(name string, status api.Status) bool {
+	if len(s.Types) > 0 && !slices.ContainsFunc(s.Types, func(t string) bool { return strings.HasSuffix(name, "-"+t) }) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if l, ok := status.Labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterStatuses returns the statuses matching the given selector
+func FilterStatuses(statuses api.Statuses, sel Selector) api.Statuses {
+	filtered := make(api.Statuses)
+	for name, status := range statuses {
+		if sel.MatchesStatus(name, status) {
+			filtered[name] = status
+		}
+	}
+	return filtered
+}
+
+// groupStatuses returns the statuses of the checks that belong to the given group
+func groupStatuses(statuses api.Statuses, group config.Group) api.Statuses {
+	sel := Selector{Types: group.Types, Labels: group.Labels}
+	bySelector := len(group.Types) > 0 || len(group.Labels) > 0
+	filtered := make(api.Statuses)
+	for name, status := range statuses {
+		if slices.Contains(group.Checks, name) || (bySelector && sel.MatchesStatus(name, status)) {
+			filtered[name] = status
+		}
+	}
+	return filtered
+}
+
+// SetGroups replaces the check groups, no groups are changed if any of them is invalid
+func (r *Runner) SetGroups(groups map[string]config.Group) error {
+	for name, group := range groups {
+		if err := group.Validate(); err != nil {
+			return fmt.Errorf("invalid group %s: %w", name, err)
+		}
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.groups = make(map[string]config.Group, len(groups))
+	for name, group := range groups {
+		r.groups[name] = group
+	}
+	return nil
+}
+
+// GetGroups returns the configured check groups
+func (r *Runner) GetGroups() map[string]config.Group {
+	r.RLock()
+	defer r.RUnlock()
+	groups := make(map[string]config.Group, len(r.groups))
+	for name, group := range r.groups {
+		groups[name] = group
+	}
+	return groups
+}
+
+// GetGroupStatus returns the statuses of the checks that belong to the given group, along with the group configuration
+func (r *Runner) GetGroupStatus(name string) (api.Statuses, config.Group, error) {
+	r.RLock()
+	defer r.RUnlock()
+	group, ok := r.groups[name]
+	if !ok {
+		return nil, config.Group{}, fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+	}
+	return groupStatuses(r.status, group), group, nil
+}
+
+// SetEvaluation replaces the policy used to evaluate the overall status
+func (r *Runner) SetEvaluation(policy config.EvaluationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid evaluation policy: %w", err)
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.evaluation = policy
+	return nil
+}
+
+// GetEvaluation returns the policy used to evaluate the overall status
+func (r *Runner) GetEvaluation() config.EvaluationPolicy {
+	r.RLock()
+	defer r.RUnlock()
+	return r.evaluation
+}
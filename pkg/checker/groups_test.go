@@ -0,0 +1,75 @@
+package checker
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestGroups(t *testing.T) {
+	statuses := api.Statuses{
+		"api-http":     {OK: true, Labels: map[string]string{"team": "payments"}},
+		"db-conn":      {OK: false, Labels: map[string]string{"team": "payments"}},
+		"cdn-http":     {OK: true, Labels: map[string]string{"team": "edge"}},
+		"resolver-dns": {OK: true},
+	}
+	tests := []struct {
+		name     string
+		group    config.Group
+		expected []string
+	}{
+		{
+			name:     "by name",
+			group:    config.Group{Checks: []string{"resolver-dns", "cdn-http"}},
+			expected: []string{"cdn-http", "resolver-dns"},
+		},
+		{
+			name:     "by labels",
+			group:    config.Group{Labels: map[string]string{"team": "payments"}},
+			expected: []string{"api-http", "db-conn"},
+		},
+		{
+			name:     "by type and labels",
+			group:    config.Group{Types: []string{"http"}, Labels: map[string]string{"team": "payments"}},
+			expected: []string{"api-http"},
+		},
+		{
+			name:     "by name or type",
+			group:    config.Group{Checks: []string{"resolver-dns"}, Types: []string{"conn"}},
+			expected: []string{"db-conn", "resolver-dns"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := maps.Keys(groupStatuses(statuses, tt.group))
+			slices.Sort(names)
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("unexpected checks, wanted: %v, got: %v", tt.expected, names)
+			}
+		})
+	}
+
+	r := &Runner{status: statuses}
+	if err := r.SetGroups(map[string]config.Group{"invalid": {}}); err == nil {
+		t.Error("expected an error for a group without checks")
+	}
+	if err := r.SetGroups(map[string]config.Group{"payments": tests[1].group}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := r.GetGroupStatus("edge"); !errors.Is(err, ErrGroupNotFound) {
+		t.Errorf("unexpected error, wanted: %v, got: %v", ErrGroupNotFound, err)
+	}
+	status, _, err := r.GetGroupStatus("payments")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary := status.Summarize(config.EvaluationPolicy{}); summary.Status != api.HealthDegraded {
+		t.Errorf("unexpected status, wanted: %s, got: %s", api.HealthDegraded, summary.Status)
+	}
+}
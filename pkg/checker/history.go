@@ -0,0 +1,142 @@
+package checker
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// uptimeWindows are the time windows over which the uptime is reported
+var uptimeWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "1h", duration: time.Hour},
+	{name: "24h", duration: 24 * time.Hour},
+	{name: "7d", duration: 7 * 24 * time.Hour},
+	{name: "30d", duration: 30 * 24 * time.Hour},
+}
+
+// history keeps the results of a check, bounded by size and age
+type history struct {
+	results   []api.Result
+	size      int
+	retention time.Duration
+	sync.RWMutex
+}
+
+func newHistory(cfg config.HistoryCfg) *history {
+	h := &history{
+		size:      cfg.Size,
+		retention: cfg.Retention.Duration,
+	}
+	if h.size <= 0 {
+		h.size = config.DefaultHistorySize
+	}
+	if h.retention <= 0 {
+		h.retention = config.DefaultHistoryRetention
+	}
+	return h
+}
+
+// add records the result from the given status
+func (h *history) add(status api.Status) {
+	h.addResult(status.Result())
+}
+
+// addResult records the given result, results that are not newer than the last recorded one are ignored
+func (h *history) addResult(result api.Result) {
+	h.Lock()
+	defer h.Unlock()
+	if n := len(h.results); n > 0 && !result.Timestamp.After(h.results[n-1].Timestamp) {
+		return
+	}
+	h.results = append(h.results, result)
+
+	// drop the results that are too old or exceed the maximum size,
+	// the backing array is reallocated, without the dropped results, as the slice grows
+	drop := len(h.results) - h.size
+	if drop < 0 {
+		drop = 0
+	}
+	oldest := result.Timestamp.Add(-h.retention)
+	for drop < len(h.results) && h.results[drop].Timestamp.Before(oldest) {
+		drop++
+	}
+	h.results = h.results[drop:]
+}
+
+// covers checks if the recorded results cover the whole window starting at the given time,
+// the results are incomplete once they're bounded by size and the oldest one is newer than the window
+func (h *history) covers(results []api.Result, window time.Duration, since time.Time) bool {
+	if window > h.retention {
+		return false
+	}
+	return len(results) < h.size || !results[0].Timestamp.After(since)
+}
+
+// report returns the recorded results along with the uptime and latency percentiles,
+// the uptime is only reported for the windows covered by the recorded results
+func (h *history) report(now time.Time) api.History {
+	h.RLock()
+	results := make([]api.Result, len(h.results))
+	copy(results, h.results)
+	h.RUnlock()
+
+	report := api.History{
+		Results: results,
+		Uptime:  make(map[string]float64),
+	}
+
+	var latencies []time.Duration
+	for _, w := range uptimeWindows {
+		since := now.Add(-w.duration)
+		if !h.covers(results, w.duration, since) {
+			continue
+		}
+		total, ok := 0, 0
+		for _, r := range results {
+			if r.Skipped || r.Silenced || r.Timestamp.Before(since) {
+				continue
+			}
+			total++
+			if r.OK {
+				ok++
+			}
+		}
+		if total > 0 {
+			report.Uptime[w.name] = float64(ok) * 100 / float64(total)
+		}
+	}
+
+	for _, r := range results {
+		if !r.Skipped {
+			latencies = append(latencies, r.Duration.Duration)
+		}
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		report.Latency = &api.Percentiles{
+			P50: percentile(latencies, 50),
+			P90: percentile(latencies, 90),
+			P95: percentile(latencies, 95),
+			P99: percentile(latencies, 99),
+		}
+	}
+
+	return report
+}
+
+// percentile returns the nearest-rank percentile of the given sorted durations
+func percentile(sorted []time.Duration, p int) metav1.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return metav1.Duration{Duration: sorted[rank-1]}
+}
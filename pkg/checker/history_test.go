@@ -0,0 +1,106 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestHistory(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		cfg     config.HistoryCfg
+		results []api.Status
+		count   int
+		uptime  map[string]float64
+		p50     time.Duration
+		p99     time.Duration
+	}{
+		{
+			name: "uptime per window",
+			results: []api.Status{
+				{Timestamp: now.Add(-48 * time.Hour), LastOK: false, Duration: metav1.Duration{Duration: 100 * time.Millisecond}},
+				{Timestamp: now.Add(-2 * time.Hour), LastOK: true, Duration: metav1.Duration{Duration: 10 * time.Millisecond}},
+				{Timestamp: now.Add(-30 * time.Minute), LastOK: false, Duration: metav1.Duration{Duration: 30 * time.Millisecond}},
+				{Timestamp: now.Add(-20 * time.Minute), Skipped: true},
+				{Timestamp: now.Add(-15 * time.Minute), LastOK: false, Silenced: true, Duration: metav1.Duration{Duration: 40 * time.Millisecond}},
+				{Timestamp: now.Add(-10 * time.Minute), LastOK: true, Duration: metav1.Duration{Duration: 20 * time.Millisecond}},
+			},
+			count:  6,
+			uptime: map[string]float64{"1h": 50, "24h": 100 * 2 / 3.0, "7d": 50, "30d": 50},
+			p50:    30 * time.Millisecond,
+			p99:    100 * time.Millisecond,
+		},
+		{
+			name: "bounded by size",
+			cfg:  config.HistoryCfg{Size: 2},
+			results: []api.Status{
+				{Timestamp: now.Add(-3 * time.Minute), LastOK: false},
+				{Timestamp: now.Add(-2 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-1 * time.Minute), LastOK: true},
+			},
+			count:  2,
+			uptime: map[string]float64{},
+		},
+		{
+			name: "bounded by size covering some windows",
+			cfg:  config.HistoryCfg{Size: 2},
+			results: []api.Status{
+				{Timestamp: now.Add(-3 * time.Hour), LastOK: false},
+				{Timestamp: now.Add(-2 * time.Hour), LastOK: true},
+				{Timestamp: now.Add(-1 * time.Minute), LastOK: true},
+			},
+			count:  2,
+			uptime: map[string]float64{"1h": 100},
+		},
+		{
+			name: "bounded by retention",
+			cfg:  config.HistoryCfg{Retention: metav1.Duration{Duration: time.Hour}},
+			results: []api.Status{
+				{Timestamp: now.Add(-3 * time.Hour), LastOK: false},
+				{Timestamp: now.Add(-10 * time.Minute), LastOK: true},
+			},
+			count:  1,
+			uptime: map[string]float64{"1h": 100},
+		},
+		{
+			name: "out of order results are ignored",
+			results: []api.Status{
+				{Timestamp: now.Add(-1 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-1 * time.Minute), LastOK: false},
+				{Timestamp: now.Add(-2 * time.Minute), LastOK: false},
+			},
+			count:  1,
+			uptime: map[string]float64{"1h": 100, "24h": 100, "7d": 100, "30d": 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(tt.cfg)
+			for _, s := range tt.results {
+				h.add(s)
+			}
+			report := h.report(now)
+			if len(report.Results) != tt.count {
+				t.Errorf("unexpected number of results, wanted: %d, got: %d", tt.count, len(report.Results))
+			}
+			if len(report.Uptime) != len(tt.uptime) {
+				t.Errorf("unexpected uptime windows, wanted: %v, got: %v", tt.uptime, report.Uptime)
+			}
+			for window, want := range tt.uptime {
+				if got := report.Uptime[window]; got != want {
+					t.Errorf("unexpected uptime for %s, wanted: %v, got: %v", window, want, got)
+				}
+			}
+			if tt.p50 != 0 && (report.Latency == nil || report.Latency.P50.Duration != tt.p50 || report.Latency.P99.Duration != tt.p99) {
+				t.Errorf("unexpected latency, wanted p50: %s and p99: %s, got: %+v", tt.p50, tt.p99, report.Latency)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var (
+	checkQueueLength = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "check_queue_length",
+		Help: "Number of checks waiting to be executed",
+	})
+
+	checkSchedulingDelay = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "check_scheduling_delay_ms",
+		Help:    "Time checks spent waiting to be executed",
+		Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000},
+	})
+)
+
+// limiter bounds the number of checks that can be executed concurrently,
+// globally and per target host, callers wait in a queue until a slot is available
+type limiter struct {
+	global  chan struct{}
+	perHost int
+	hosts   map[string]chan struct{}
+	sync.Mutex
+}
+
+func newLimiter(cfg config.ConcurrencyCfg) *limiter {
+	l := &limiter{
+		perHost: cfg.MaxChecksPerHost,
+		hosts:   make(map[string]chan struct{}),
+	}
+	if cfg.MaxChecks > 0 {
+		l.global = make(chan struct{}, cfg.MaxChecks)
+	}
+	return l
+}
+
+// hostSlots returns the semaphore for the given host, or nil if there's no limit
+func (l *limiter) hostSlots(host string) chan struct{} {
+	if l.perHost <= 0 || host == "" {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+	slots, ok := l.hosts[host]
+	if !ok {
+		slots = make(chan struct{}, l.perHost)
+		l.hosts[host] = slots
+	}
+	return slots
+}
+
+// acquire waits for a slot to execute a check targeting the given host,
+// the returned function must be called to release the slot
+func (l *limiter) acquire(ctx context.Context, host string) (func(), error) {
+	hostSlots := l.hostSlots(host)
+	if l.global == nil && hostSlots == nil {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	checkQueueLength.Inc()
+	defer checkQueueLength.Dec()
+
+	// the host slot is acquired first to avoid holding a global slot while waiting for a busy host
+	if hostSlots != nil {
+		select {
+		case hostSlots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if l.global != nil {
+		select {
+		case l.global <- struct{}{}:
+		case <-ctx.Done():
+			if hostSlots != nil {
+				<-hostSlots
+			}
+			return nil, ctx.Err()
+		}
+	}
+	checkSchedulingDelay.Observe(float64(time.Since(start).Milliseconds()))
+
+	return func() {
+		if l.global != nil {
+			<-l.global
+		}
+		if hostSlots != nil {
+			<-hostSlots
+		}
+	}, nil
+}
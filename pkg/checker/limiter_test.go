@@ -0,0 +1,121 @@
+package checker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestLimiter(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.ConcurrencyCfg
+		hosts    []string
+		expected int32
+	}{
+		{
+			name:     "unlimited",
+			config:   config.ConcurrencyCfg{},
+			hosts:    []string{"a", "a", "a", "b", "b", "b"},
+			expected: 6,
+		},
+		{
+			name:     "global limit",
+			config:   config.ConcurrencyCfg{MaxChecks: 2},
+			hosts:    []string{"a", "b", "c", "d", "e", "f"},
+			expected: 2,
+		},
+		{
+			name:     "per host limit",
+			config:   config.ConcurrencyCfg{MaxChecksPerHost: 1},
+			hosts:    []string{"a", "a", "a", "a", "b", "b"},
+			expected: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newLimiter(tt.config)
+			var running, maxRunning int32
+			var wg sync.WaitGroup
+			for _, host := range tt.hosts {
+				wg.Add(1)
+				go func(host string) {
+					defer wg.Done()
+					release, err := l.acquire(context.TODO(), host)
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+						return
+					}
+					cur := atomic.AddInt32(&running, 1)
+					for {
+						prev := atomic.LoadInt32(&maxRunning)
+						if cur <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, cur) {
+							break
+						}
+					}
+					time.Sleep(20 * time.Millisecond)
+					atomic.AddInt32(&running, -1)
+					release()
+				}(host)
+			}
+			wg.Wait()
+			if maxRunning != tt.expected {
+				t.Errorf("unexpected number of concurrent checks, wanted: %d, got: %d", tt.expected, maxRunning)
+			}
+		})
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(config.ConcurrencyCfg{MaxChecks: 1})
+	release, err := l.acquire(context.TODO(), "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(ctx, ""); err == nil {
+		t.Errorf("expected an error waiting for a busy slot")
+	}
+}
+
+func TestLimiterRetries(t *testing.T) {
+	c, err := NewFromConfig(config.Config{Concurrency: config.ConcurrencyCfg{MaxChecks: 1}}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.AddCheck("retried", &stubCheck{
+		ok:  false,
+		cfg: config.BaseCheck{Retries: 1, RetryDelay: metav1.Duration{Duration: 200 * time.Millisecond}},
+	}, false)
+	c.AddCheck("other", &stubCheck{ok: true}, false)
+
+	done := make(chan struct{})
+	go func() {
+		c.check(context.TODO(), "retried", c.checks["retried"])
+		close(done)
+	}()
+	// wait for the first attempt to fail, the slot must be released while waiting to retry
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	c.check(context.TODO(), "other", c.checks["other"])
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("the check waited for the retry delay of another check: %s", elapsed)
+	}
+	<-done
+	if status, _ := c.GetStatusFor("retried"); status.Attempts != 2 {
+		t.Errorf("unexpected number of attempts, wanted: 2, got: %d", status.Attempts)
+	}
+}
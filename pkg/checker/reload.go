@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+	"github.com/luisdavim/synthetic-checker/pkg/notifier"
+)
+
+// Reload applies a new configuration, only the checks and silences that were added, changed or removed
+// since the last configuration was applied are touched, other checks keep running undisturbed.
+// Checks and silences added through the API are not affected, the groups, evaluation policy and notifiers are replaced.
+// Changes to the concurrency, history, metrics and store settings require a restart, they're logged and ignored.
+func (r *Runner) Reload(cfg config.Config) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	if err := cfg.Evaluation.Validate(); err != nil {
+		return fmt.Errorf("invalid evaluation policy: %w", err)
+	}
+	for name, group := range cfg.Groups {
+		if err := group.Validate(); err != nil {
+			return fmt.Errorf("invalid group %s: %w", name, err)
+		}
+	}
+
+	var (
+		changes config.Config
+		removed []string
+	)
+	changes.HTTPChecks, removed = diff(r.cfg.HTTPChecks, cfg.HTTPChecks, "http", removed)
+	changes.GRPCChecks, removed = diff(r.cfg.GRPCChecks, cfg.GRPCChecks, "grpc", removed)
+	changes.DNSChecks, removed = diff(r.cfg.DNSChecks, cfg.DNSChecks, "dns", removed)
+	changes.ConnChecks, removed = diff(r.cfg.ConnChecks, cfg.ConnChecks, "conn", removed)
+	changes.TLSChecks, removed = diff(r.cfg.TLSChecks, cfg.TLSChecks, "tls", removed)
+	changes.K8sChecks, removed = diff(r.cfg.K8sChecks, cfg.K8sChecks, "k8s", removed)
+	changes.K8sPings, removed = diff(r.cfg.K8sPings, cfg.K8sPings, "k8sping", removed)
+	changes.WebSocketChecks, removed = diff(r.cfg.WebSocketChecks, cfg.WebSocketChecks, "websocket", removed)
+	changes.GraphQLChecks, removed = diff(r.cfg.GraphQLChecks, cfg.GraphQLChecks, "graphql", removed)
+	changes.HeartbeatChecks, removed = diff(r.cfg.HeartbeatChecks, cfg.HeartbeatChecks, "heartbeat", removed)
+	changes.PromQueryChecks, removed = diff(r.cfg.PromQueryChecks, cfg.PromQueryChecks, "promquery", removed)
+	changes.CompositeChecks, removed = diff(r.cfg.CompositeChecks, cfg.CompositeChecks, "composite", removed)
+
+	var removedSilences []string
+	changes.Silences, removedSilences = diff(r.cfg.Silences, cfg.Silences, "", nil)
+
+	// create all the checks and notifiers before touching the running ones, so that an invalid configuration is not partially applied
+	staged, err := r.newChecks(changes)
+	if err != nil {
+		return err
+	}
+	var n *notifier.Notifier
+	if !cfg.Notifiers.Equal(r.cfg.Notifiers) {
+		if n, err = notifier.New(cfg.Notifiers); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range removed {
+		r.DelCheck(name)
+	}
+	for name, check := range staged {
+		r.AddCheck(name, check, r.started)
+	}
+	r.warnUnknownDependencies()
+	for _, name := range removedSilences {
+		_ = r.DelSilence(name)
+	}
+	for name, silence := range changes.Silences {
+		if err := r.AddSilence(name, silence); err != nil {
+			return err
+		}
+	}
+
+	if err := r.SetGroups(cfg.Groups); err != nil {
+		return err
+	}
+	if err := r.SetEvaluation(cfg.Evaluation); err != nil {
+		return err
+	}
+
+	if n != nil {
+		r.Lock()
+		r.notifier.Stop()
+		r.notifier = n
+		r.Unlock()
+		n.Start(r)
+	}
+
+	if ignored := restartRequired(r.cfg, cfg); len(ignored) > 0 {
+		r.log.Warn().Strs("settings", ignored).Msg("ignoring configuration changes that require a restart")
+		// keep the settings in use, so that the changes are reported until they're applied
+		cfg.Concurrency, cfg.History, cfg.Metrics, cfg.Store = r.cfg.Concurrency, r.cfg.History, r.cfg.Metrics, r.cfg.Store
+	}
+
+	r.cfg = cfg
+	r.log.Info().Int("changed", len(staged)).Int("removed", len(removed)).Msg("configuration reloaded")
+	return nil
+}
+
+// restartRequired returns the names of the changed settings that can't be applied without a restart
+func restartRequired(old, new config.Config) []string {
+	var changed []string
+	if old.Concurrency != new.Concurrency {
+		changed = append(changed, "concurrency")
+	}
+	if old.History != new.History {
+		changed = append(changed, "history")
+	}
+	if !slices.Equal(old.Metrics.Labels, new.Metrics.Labels) {
+		changed = append(changed, "metrics")
+	}
+	if old.Store != new.Store {
+		changed = append(changed, "store")
+	}
+	return changed
+}
+
+// diff returns the items that were added or changed in the new map
+// and appends the names of the removed ones, with the given suffix, to removed
+func diff[T interface{ Equal(T) bool }](old, new map[string]T, suffix string, removed []string) (map[string]T, []string) {
+	changed := make(map[string]T)
+	for name, cfg := range new {
+		if cur, ok := old[name]; !ok || !cur.Equal(cfg) {
+			changed[name] = cfg
+		}
+	}
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			if suffix != "" {
+				name += "-" + suffix
+			}
+			removed = append(removed, name)
+		}
+	}
+	return changed, removed
+}
@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestReload(t *testing.T) {
+	c, err := NewFromConfig(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"unchanged": {URL: "http://fake.com/unchanged"},
+			"changed":   {URL: "http://fake.com/changed"},
+			"removed":   {URL: "http://fake.com/removed"},
+		},
+		Silences: map[string]config.Silence{
+			"old": {Types: []string{"http"}},
+		},
+	}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.updateStatusFor("unchanged-http", nil, api.Status{OK: true, Timestamp: time.Now()})
+	unchanged := c.checks["unchanged-http"]
+	changed := c.checks["changed-http"]
+
+	err = c.Reload(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"unchanged": {URL: "http://fake.com/unchanged"},
+			"changed": {
+				URL:       "http://fake.com/changed",
+				BaseCheck: config.BaseCheck{Interval: metav1.Duration{Duration: time.Minute}},
+			},
+		},
+		ConnChecks: map[string]config.ConnCheck{
+			"added": {Address: "fake.com:443"},
+		},
+		Silences: map[string]config.Silence{
+			"new": {Types: []string{"conn"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if c.checks["unchanged-http"] != unchanged {
+		t.Errorf("the unchanged check was replaced")
+	}
+	if _, ok := c.GetStatusFor("unchanged-http"); !ok {
+		t.Errorf("the status of the unchanged check was lost")
+	}
+	if c.checks["changed-http"] == changed || c.checks["changed-http"].Interval().Duration != time.Minute {
+		t.Errorf("the changed check was not replaced")
+	}
+	if _, ok := c.checks["removed-http"]; ok {
+		t.Errorf("the removed check is still configured")
+	}
+	if _, ok := c.checks["added-conn"]; !ok {
+		t.Errorf("the added check is not configured")
+	}
+	silences := c.GetSilences()
+	if _, ok := silences["old"]; ok {
+		t.Errorf("the removed silence is still configured")
+	}
+	if _, ok := silences["new"]; !ok {
+		t.Errorf("the added silence is not configured")
+	}
+
+	// invalid configurations are not applied
+	err = c.Reload(config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"invalid": {},
+		},
+	})
+	if err == nil {
+		t.Errorf("expected an error for an invalid configuration")
+	}
+	if _, ok := c.checks["unchanged-http"]; !ok {
+		t.Errorf("an invalid configuration was partially applied")
+	}
+}
+
+func TestReloadRestartRequired(t *testing.T) {
+	c, err := NewFromConfig(config.Config{Concurrency: config.ConcurrencyCfg{MaxChecks: 10}}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg := config.Config{
+		Concurrency: config.ConcurrencyCfg{MaxChecks: 20},
+		Metrics:     config.MetricsCfg{Labels: []string{"team"}},
+	}
+	if changed := restartRequired(c.cfg, cfg); len(changed) != 2 || changed[0] != "concurrency" || changed[1] != "metrics" {
+		t.Errorf("unexpected settings requiring a restart, wanted: [concurrency metrics], got: %v", changed)
+	}
+	if err := c.Reload(cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.cfg.Concurrency.MaxChecks != 10 || len(c.cfg.Metrics.Labels) != 0 {
+		t.Errorf("expected the settings in use to be kept, got: %+v, %+v", c.cfg.Concurrency, c.cfg.Metrics)
+	}
+}
@@ -0,0 +1,84 @@
+package checker
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// scheduler computes when a check should be executed next
+type scheduler struct {
+	interval time.Duration
+	cron     cron.Schedule
+	loc      *time.Location
+	jitter   int
+}
+
+// newScheduler creates a scheduler for the given check,
+// invalid cron expressions or time zones are ignored as they're validated when creating the checks
+func newScheduler(check api.Check) *scheduler {
+	cfg := check.BaseConfig()
+	s := &scheduler{
+		interval: check.Interval().Duration,
+		jitter:   cfg.Jitter,
+		loc:      time.UTC,
+	}
+	if loc, err := cfg.Location(); err == nil {
+		s.loc = loc
+	}
+	if sched, err := cfg.CronSchedule(); err == nil {
+		s.cron = sched
+	}
+	return s
+}
+
+// first returns how long to wait before the first execution of the check,
+// checks using a fixed interval run straight away, unless a jitter is configured,
+// in which case it's applied once, as a random offset, so that the checks keep running every interval
+func (s *scheduler) first(now time.Time) time.Duration {
+	if s.cron != nil {
+		return s.next(now)
+	}
+	return s.randomDelay(s.interval)
+}
+
+// next returns how long to wait before the next execution of the check,
+// cron scheduled executions are randomly delayed within the jitter, without shifting the following ones
+func (s *scheduler) next(now time.Time) time.Duration {
+	if s.cron == nil {
+		return s.interval
+	}
+	next := s.cron.Next(now.In(s.loc))
+	period := s.cron.Next(next).Sub(next)
+	return next.Sub(now) + s.randomDelay(period)
+}
+
+// randomDelay returns a random delay of up to the configured jitter percentage of the given period
+func (s *scheduler) randomDelay(period time.Duration) time.Duration {
+	maxDelay := int64(period) * int64(s.jitter) / 100
+	if maxDelay <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(maxDelay))
+}
+
+// isActive checks if the given time is within any of the check's active windows,
+// checks without active windows are always active
+func isActive(cfg config.BaseCheck, t time.Time) bool {
+	if len(cfg.ActiveWindows) == 0 {
+		return true
+	}
+	if loc, err := cfg.Location(); err == nil {
+		t = t.In(loc)
+	}
+	for _, w := range cfg.ActiveWindows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
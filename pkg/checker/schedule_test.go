@@ -0,0 +1,152 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestScheduler(t *testing.T) {
+	now := time.Date(2023, time.January, 2, 10, 0, 30, 0, time.UTC)
+	tests := []struct {
+		name     string
+		config   config.BaseCheck
+		min, max time.Duration
+		firstMax time.Duration
+	}{
+		{
+			name: "interval",
+			config: config.BaseCheck{
+				Interval: metav1.Duration{Duration: time.Minute},
+			},
+			min: time.Minute,
+			max: time.Minute,
+		},
+		{
+			name: "interval with jitter",
+			config: config.BaseCheck{
+				Interval: metav1.Duration{Duration: time.Minute},
+				Jitter:   50,
+			},
+			min:      time.Minute,
+			max:      time.Minute,
+			firstMax: 30 * time.Second,
+		},
+		{
+			name: "cron",
+			config: config.BaseCheck{
+				Schedule: "*/5 * * * *",
+			},
+			min: 4*time.Minute + 30*time.Second,
+			max: 4*time.Minute + 30*time.Second,
+		},
+		{
+			name: "cron with jitter",
+			config: config.BaseCheck{
+				Schedule: "*/5 * * * *",
+				Jitter:   10,
+			},
+			min: 4*time.Minute + 30*time.Second,
+			max: 5 * time.Minute,
+		},
+		{
+			name: "cron with timezone",
+			config: config.BaseCheck{
+				Schedule: "0 12 * * *",
+				Timezone: "Europe/Lisbon",
+			},
+			min: 119*time.Minute + 30*time.Second,
+			max: 119*time.Minute + 30*time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check, err := checks.NewHeartbeatCheck("test", config.HeartbeatCheck{BaseCheck: tt.config})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			s := newScheduler(check)
+			for i := 0; i < 10; i++ {
+				if next := s.next(now); next < tt.min || next > tt.max {
+					t.Errorf("unexpected delay, wanted between %s and %s, got: %s", tt.min, tt.max, next)
+				}
+			}
+			if s.cron == nil {
+				if first := s.first(now); first < 0 || first > tt.firstMax {
+					t.Errorf("unexpected first delay, wanted up to %s, got: %s", tt.firstMax, first)
+				}
+			}
+		})
+	}
+}
+
+func TestIsActive(t *testing.T) {
+	// 2023-01-02 is a Monday
+	monday := func(hour, min int) time.Time {
+		return time.Date(2023, time.January, 2, hour, min, 0, 0, time.UTC)
+	}
+	businessHours := []config.TimeWindow{{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "17:30"}}
+	overnight := []config.TimeWindow{{Days: []string{"sunday"}, Start: "22:00", End: "02:00"}}
+	tests := []struct {
+		name     string
+		config   config.BaseCheck
+		at       time.Time
+		expected bool
+	}{
+		{
+			name:     "no windows",
+			config:   config.BaseCheck{},
+			at:       monday(3, 0),
+			expected: true,
+		},
+		{
+			name:     "within business hours",
+			config:   config.BaseCheck{ActiveWindows: businessHours},
+			at:       monday(9, 0),
+			expected: true,
+		},
+		{
+			name:     "after business hours",
+			config:   config.BaseCheck{ActiveWindows: businessHours},
+			at:       monday(17, 30),
+			expected: false,
+		},
+		{
+			name:     "weekend",
+			config:   config.BaseCheck{ActiveWindows: businessHours},
+			at:       monday(12, 0).Add(-48 * time.Hour),
+			expected: false,
+		},
+		{
+			name:     "business hours in another timezone",
+			config:   config.BaseCheck{ActiveWindows: businessHours, Timezone: "America/New_York"},
+			at:       monday(12, 0),
+			expected: false,
+		},
+		{
+			name:     "overnight window started the previous day",
+			config:   config.BaseCheck{ActiveWindows: overnight},
+			at:       monday(1, 0),
+			expected: true,
+		},
+		{
+			name:     "overnight window on the wrong day",
+			config:   config.BaseCheck{ActiveWindows: overnight},
+			at:       monday(23, 0),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := isActive(tt.config, tt.at); actual != tt.expected {
+				t.Errorf("unexpected result, wanted: %t, got: %t", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// burnRateWindows are the time windows over which the error budget burn rate is reported,
+// they're meant to be combined in multi-window alerts, e.g.: 1h and 5m for fast burns or 3d and 6h for slow burns
+var burnRateWindows = []struct {
+	name     string
+	duration time.Duration
+}{
+	{name: "5m", duration: 5 * time.Minute},
+	{name: "30m", duration: 30 * time.Minute},
+	{name: "1h", duration: time.Hour},
+	{name: "6h", duration: 6 * time.Hour},
+	{name: "1d", duration: 24 * time.Hour},
+	{name: "3d", duration: 3 * 24 * time.Hour},
+}
+
+// slo evaluates the given service level objective against the recorded results
+func (h *history) slo(cfg config.SLO, now time.Time) api.SLOStatus {
+	window := cfg.Window.Duration
+	if window <= 0 {
+		window = config.DefaultSLOWindow
+	}
+	status := api.SLOStatus{
+		Target:               cfg.Target,
+		Window:               metav1.Duration{Duration: window},
+		SLI:                  100,
+		ErrorBudgetRemaining: 1,
+		BurnRates:            make(map[string]float64),
+	}
+	budget := 1 - cfg.Target/100
+
+	h.RLock()
+	defer h.RUnlock()
+	if !h.covers(h.results, window, now.Add(-window)) {
+		status.EffectiveWindow = &metav1.Duration{Duration: h.effectiveWindow(now)}
+	}
+	if errorRate, ok := h.errorRate(now.Add(-window)); ok {
+		status.SLI = (1 - errorRate) * 100
+		status.ErrorBudgetRemaining = 1 - errorRate/budget
+	}
+	for _, w := range burnRateWindows {
+		if errorRate, ok := h.errorRate(now.Add(-w.duration)); ok {
+			status.BurnRates[w.name] = errorRate / budget
+		}
+	}
+	return status
+}
+
+// effectiveWindow returns the longest window the recorded results can cover,
+// it must be called with the history lock held
+func (h *history) effectiveWindow(now time.Time) time.Duration {
+	window := h.retention
+	if len(h.results) >= h.size {
+		if covered := now.Sub(h.results[0].Timestamp); covered < window {
+			window = covered
+		}
+	}
+	return window
+}
+
+// errorRate returns the ratio of failed executions since the given time,
+// it must be called with the history lock held
+func (h *history) errorRate(since time.Time) (float64, bool) {
+	total, failed := 0, 0
+	for i := len(h.results) - 1; i >= 0 && !h.results[i].Timestamp.Before(since); i-- {
+		r := h.results[i]
+		if r.Skipped || r.Silenced {
+			continue
+		}
+		total++
+		if !r.OK {
+			failed++
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	return float64(failed) / float64(total), true
+}
+
+// GetSLOFor returns the state of the service level objective of the given check,
+// it returns false if the check doesn't exist or doesn't have an SLO
+func (r *Runner) GetSLOFor(name string) (api.SLOStatus, bool) {
+	r.RLock()
+	check, ok := r.checks[name]
+	h, found := r.history[name]
+	r.RUnlock()
+	if !ok {
+		return api.SLOStatus{}, false
+	}
+	cfg := check.BaseConfig().SLO
+	if cfg.Target == 0 {
+		return api.SLOStatus{}, false
+	}
+	if !found {
+		h = newHistory(r.historyCfg)
+	}
+	return h.slo(cfg, time.Now()), true
+}
+
+// updateSLOMetricsFor generates Prometheus metrics from the given SLO state
+func (r *Runner) updateSLOMetricsFor(name string, labels map[string]string, slo api.SLOStatus) {
+	metricLabels := r.metricLabelsFor(name, labels)
+	checkSLOBudget.With(metricLabels).Set(slo.ErrorBudgetRemaining)
+	for window, rate := range slo.BurnRates {
+		checkSLOBurnRate.With(withLabel(metricLabels, "window", window)).Set(rate)
+	}
+}
@@ -0,0 +1,185 @@
+package checker
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestSLO(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		results   []api.Status
+		sli       float64
+		budget    float64
+		burnRates map[string]float64
+	}{
+		{
+			name:      "no executions",
+			sli:       100,
+			budget:    1,
+			burnRates: map[string]float64{},
+		},
+		{
+			name: "budget exhausted",
+			results: []api.Status{
+				{Timestamp: now.Add(-10 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-9 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-8 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-7 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-6 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-5 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-4 * time.Minute), LastOK: true},
+				{Timestamp: now.Add(-3 * time.Minute), Skipped: true},
+				{Timestamp: now.Add(-150 * time.Second), LastOK: false, Silenced: true},
+				{Timestamp: now.Add(-2 * time.Minute), LastOK: false},
+				{Timestamp: now.Add(-1 * time.Minute), LastOK: true},
+				{Timestamp: now, LastOK: true},
+			},
+			sli:       90,
+			budget:    0,
+			burnRates: map[string]float64{"5m": 2, "30m": 1, "1h": 1, "6h": 1, "1d": 1, "3d": 1},
+		},
+		{
+			name: "budget partially consumed",
+			results: []api.Status{
+				{Timestamp: now.Add(-4 * 24 * time.Hour), LastOK: false},
+				{Timestamp: now.Add(-3 * time.Hour), LastOK: true},
+				{Timestamp: now.Add(-2 * time.Hour), LastOK: true},
+				{Timestamp: now.Add(-1 * time.Hour), LastOK: true},
+			},
+			sli:       75,
+			budget:    -1.5,
+			burnRates: map[string]float64{"1h": 0, "6h": 0, "1d": 0, "3d": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(config.HistoryCfg{})
+			for _, status := range tt.results {
+				h.add(status)
+			}
+			slo := h.slo(config.SLO{Target: 90}, now)
+			if slo.Window.Duration != config.DefaultSLOWindow {
+				t.Errorf("unexpected window, wanted: %s, got: %s", config.DefaultSLOWindow, slo.Window.Duration)
+			}
+			if !almostEqual(slo.SLI, tt.sli) {
+				t.Errorf("unexpected SLI, wanted: %v, got: %v", tt.sli, slo.SLI)
+			}
+			if !almostEqual(slo.ErrorBudgetRemaining, tt.budget) {
+				t.Errorf("unexpected error budget, wanted: %v, got: %v", tt.budget, slo.ErrorBudgetRemaining)
+			}
+			if len(slo.BurnRates) != len(tt.burnRates) {
+				t.Errorf("unexpected burn rates, wanted: %v, got: %v", tt.burnRates, slo.BurnRates)
+			}
+			for w, rate := range tt.burnRates {
+				if !almostEqual(slo.BurnRates[w], rate) {
+					t.Errorf("unexpected %s burn rate, wanted: %v, got: %v", w, rate, slo.BurnRates[w])
+				}
+			}
+		})
+	}
+}
+
+func TestSLOEffectiveWindow(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	results := []api.Status{
+		{Timestamp: now.Add(-3 * time.Hour), LastOK: true},
+		{Timestamp: now.Add(-2 * time.Hour), LastOK: true},
+		{Timestamp: now.Add(-1 * time.Hour), LastOK: true},
+	}
+	tests := []struct {
+		name     string
+		history  config.HistoryCfg
+		window   time.Duration
+		expected *time.Duration
+	}{
+		{
+			name:   "covered",
+			window: 24 * time.Hour,
+		},
+		{
+			name:     "bounded by size",
+			history:  config.HistoryCfg{Size: 2},
+			window:   24 * time.Hour,
+			expected: durationPtr(2 * time.Hour),
+		},
+		{
+			name:     "bounded by retention",
+			history:  config.HistoryCfg{Retention: metav1.Duration{Duration: 24 * time.Hour}},
+			window:   48 * time.Hour,
+			expected: durationPtr(24 * time.Hour),
+		},
+		{
+			name:    "size reached within the window",
+			history: config.HistoryCfg{Size: 3},
+			window:  3 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHistory(tt.history)
+			for _, status := range results {
+				h.add(status)
+			}
+			slo := h.slo(config.SLO{Target: 90, Window: metav1.Duration{Duration: tt.window}}, now)
+			switch {
+			case tt.expected == nil && slo.EffectiveWindow != nil:
+				t.Errorf("unexpected effective window, wanted none, got: %s", slo.EffectiveWindow.Duration)
+			case tt.expected != nil && slo.EffectiveWindow == nil:
+				t.Errorf("missing effective window, wanted: %s", *tt.expected)
+			case tt.expected != nil && slo.EffectiveWindow.Duration != *tt.expected:
+				t.Errorf("unexpected effective window, wanted: %s, got: %s", *tt.expected, slo.EffectiveWindow.Duration)
+			}
+		})
+	}
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestSLOMetrics(t *testing.T) {
+	c, err := NewFromConfig(config.Config{}, false)
+	defer func() {
+		// avoid panic with the prometheus.MustRegister used in NewFromConfig
+		unregisterMetrics()
+	}()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.AddCheck(checkName, &stubCheck{ok: true, cfg: config.BaseCheck{SLO: config.SLO{Target: 90}}}, false)
+	c.check(context.TODO(), checkName, c.checks[checkName])
+	c.checks[checkName].(*stubCheck).ok = false
+	c.check(context.TODO(), checkName, c.checks[checkName])
+
+	slo, ok := c.GetSLOFor(checkName)
+	if !ok {
+		t.Fatalf("SLO not found")
+	}
+	if v := testutil.ToFloat64(checkSLOBudget.WithLabelValues(checkName)); !almostEqual(v, slo.ErrorBudgetRemaining) || !almostEqual(v, -4) {
+		t.Errorf("unexpected error budget metric, wanted: %v, got: %v", -4, v)
+	}
+	if v := testutil.ToFloat64(checkSLOBurnRate.WithLabelValues("5m", checkName)); !almostEqual(v, 5) {
+		t.Errorf("unexpected burn rate metric, wanted: %v, got: %v", 5, v)
+	}
+	history, _ := c.GetHistoryFor(checkName)
+	if history.SLO == nil {
+		t.Errorf("the SLO is missing from the history")
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
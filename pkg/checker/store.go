@@ -0,0 +1,87 @@
+package checker
+
+import (
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// restoreState loads the statuses, history and checks added at runtime from the store
+func (r *Runner) restoreState(start bool) error {
+	statuses, err := r.store.Statuses()
+	if err != nil {
+		return err
+	}
+	for name, status := range statuses {
+		results, err := r.store.History(name)
+		if err != nil {
+			return err
+		}
+		h := newHistory(r.historyCfg)
+		for _, result := range results {
+			h.addResult(result)
+		}
+		r.Lock()
+		r.status[name] = status
+		r.history[name] = h
+		r.Unlock()
+	}
+
+	configs, err := r.store.Configs()
+	if err != nil {
+		return err
+	}
+	for name, cfg := range configs {
+		if err := r.AddFromConfig(cfg, start); err != nil {
+			r.log.Err(err).Str("name", name).Msg("restoring check")
+		}
+	}
+	return nil
+}
+
+// restoreHeartbeats loads the pings received by the heartbeat checks from the store
+// and drops the state of the checks that no longer exist
+func (r *Runner) restoreHeartbeats() error {
+	states, err := r.store.Heartbeats()
+	if err != nil {
+		return err
+	}
+	r.Lock()
+	defer r.Unlock()
+	for name, state := range states {
+		if hb, ok := r.checks[name].(checks.Heartbeat); ok {
+			hb.Restore(state)
+		}
+	}
+	for name := range r.status {
+		if _, ok := r.checks[name]; ok {
+			continue
+		}
+		delete(r.status, name)
+		delete(r.history, name)
+		if err := r.store.Delete(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Persist stores the configuration of checks added at runtime, so that they're restored after a restart,
+// the name must match the name reported in the status, e.g.: "example-http"
+func (r *Runner) Persist(name string, cfg config.Config) error {
+	if r.store == nil {
+		return nil
+	}
+	return r.store.SaveConfig(name, cfg)
+}
+
+// Close releases the resources used by the runner, including the event subscriptions, it should be called after Stop
+func (r *Runner) Close() error {
+	r.Lock()
+	r.notifier.Stop()
+	r.Unlock()
+	r.events.close()
+	if r.store == nil {
+		return nil
+	}
+	return r.store.Close()
+}
@@ -0,0 +1,335 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var _ Composite = &compositeCheck{}
+
+const (
+	compositeAll     = "all"
+	compositeAny     = "any"
+	compositeAtLeast = "atLeast"
+)
+
+// StatusGetter returns the last known status of the given check
+type StatusGetter func(name string) (api.Status, bool)
+
+// Composite is implemented by the checks that combine the statuses of other checks
+type Composite interface {
+	api.Check
+	// Inputs returns the names of the checks whose statuses are combined
+	Inputs() []string
+}
+
+type compositeCheck struct {
+	name      string
+	config    *config.CompositeCheck
+	getStatus StatusGetter
+	expr      expression
+}
+
+// NewCompositeCheck returns a check that combines the statuses of other checks,
+// the statuses are read through the given StatusGetter
+func NewCompositeCheck(name string, config config.CompositeCheck, getStatus StatusGetter) (api.Check, error) {
+	if name == "" {
+		return nil, fmt.Errorf("CheckName must not be empty")
+	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
+	if getStatus == nil {
+		return nil, fmt.Errorf("a status getter is required")
+	}
+	if config.Interval.Duration == 0 {
+		config.Interval = metav1.Duration{Duration: 30 * time.Second}
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout = metav1.Duration{Duration: time.Second}
+	}
+
+	c := &compositeCheck{
+		name:      name,
+		config:    &config,
+		getStatus: getStatus,
+	}
+
+	if config.Expression != "" {
+		expr, err := parseExpression(config.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression: %w", err)
+		}
+		c.expr = expr
+		return c, nil
+	}
+
+	if len(config.Checks) == 0 {
+		return nil, fmt.Errorf("either checks or expression must be set")
+	}
+	if config.Mode == "" {
+		config.Mode = compositeAll
+	}
+	switch config.Mode {
+	case compositeAll, compositeAny:
+	case compositeAtLeast:
+		if config.AtLeast < 1 || config.AtLeast > len(config.Checks) {
+			return nil, fmt.Errorf("atLeast must be between 1 and the number of checks (%d)", len(config.Checks))
+		}
+	default:
+		return nil, fmt.Errorf("unknown mode %q", config.Mode)
+	}
+
+	return c, nil
+}
+
+func (c *compositeCheck) Equal(other *compositeCheck) bool {
+	return c.config.Equal(*other.config)
+}
+
+func (c *compositeCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.config)
+	if err != nil {
+		return "", "", "", err
+	}
+	return "composite", c.name, string(b), nil
+}
+
+// Interval indicates how often the check should be performed
+func (c *compositeCheck) Interval() metav1.Duration {
+	return c.config.Interval
+}
+
+// InitialDelay indicates how long to delay the check start
+func (c *compositeCheck) InitialDelay() metav1.Duration {
+	return c.config.InitialDelay
+}
+
+// BaseConfig returns the settings common to all checks
+func (c *compositeCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Inputs returns the names of the checks whose statuses are combined
+func (c *compositeCheck) Inputs() []string {
+	if c.expr == nil {
+		return c.config.Checks
+	}
+	// all the operands are evaluated, so every name in the expression is visited
+	var names []string
+	c.expr.eval(func(name string) bool {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+		return true
+	})
+	return names
+}
+
+// Execute evaluates the statuses of the referenced checks
+func (c *compositeCheck) Execute(ctx context.Context) (bool, error) {
+	var failed []string
+	isOK := func(name string) bool {
+		status, found := c.getStatus(name)
+		if !found {
+			failed = append(failed, name+" (unknown)")
+			return false
+		}
+		if !status.OK {
+			failed = append(failed, name)
+		}
+		return status.OK
+	}
+
+	if c.expr != nil {
+		if !c.expr.eval(isOK) {
+			return false, fmt.Errorf("expression %q evaluated to false, failed checks: %s", c.config.Expression, strings.Join(failed, ", "))
+		}
+		return true, nil
+	}
+
+	passed := 0
+	for _, name := range c.config.Checks {
+		if isOK(name) {
+			passed++
+		}
+	}
+
+	var ok bool
+	switch c.config.Mode {
+	case compositeAny:
+		ok = passed > 0
+	case compositeAtLeast:
+		ok = passed >= c.config.AtLeast
+	default:
+		ok = passed == len(c.config.Checks)
+	}
+	if !ok {
+		return false, fmt.Errorf("%d of %d checks passed (mode: %s), failed checks: %s", passed, len(c.config.Checks), c.config.Mode, strings.Join(failed, ", "))
+	}
+
+	return true, nil
+}
+
+// expression is a parsed boolean expression over check names
+type expression interface {
+	eval(isOK func(name string) bool) bool
+}
+
+type (
+	identExpr string
+	notExpr   struct{ x expression }
+	andExpr   struct{ l, r expression }
+	orExpr    struct{ l, r expression }
+)
+
+func (e identExpr) eval(isOK func(string) bool) bool { return isOK(string(e)) }
+func (e notExpr) eval(isOK func(string) bool) bool   { return !e.x.eval(isOK) }
+
+// both sides are always evaluated so that all the failed checks are reported
+func (e andExpr) eval(isOK func(string) bool) bool {
+	l, r := e.l.eval(isOK), e.r.eval(isOK)
+	return l && r
+}
+
+func (e orExpr) eval(isOK func(string) bool) bool {
+	l, r := e.l.eval(isOK), e.r.eval(isOK)
+	return l || r
+}
+
+// exprParser is a small recursive descent parser for expressions with the following grammar:
+//
+//	or   = and { "||" and }
+//	and  = unary { "&&" unary }
+//	unary = "!" unary | "(" or ")" | name
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func parseExpression(s string) (expression, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		ch := rune(s[i])
+		switch {
+		case unicode.IsSpace(ch):
+			i++
+		case ch == '(' || ch == ')' || ch == '!':
+			tokens = append(tokens, string(ch))
+			i++
+		case strings.HasPrefix(s[i:], "&&") || strings.HasPrefix(s[i:], "||"):
+			tokens = append(tokens, s[i:i+2])
+			i += 2
+		case isNameChar(ch):
+			j := i
+			for j < len(s) && isNameChar(rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", ch, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isNameChar(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '-' || ch == '_' || ch == '.'
+}
+
+func (p *exprParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) parseOr() (expression, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.next() == "||" {
+		p.pos++
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orExpr{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseAnd() (expression, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.next() == "&&" {
+		p.pos++
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = andExpr{l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseUnary() (expression, error) {
+	tok := p.next()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "!":
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x: x}, nil
+	case "(":
+		p.pos++
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return x, nil
+	case ")", "&&", "||":
+		return nil, fmt.Errorf("unexpected token %q", tok)
+	default:
+		p.pos++
+		return identExpr(tok), nil
+	}
+}
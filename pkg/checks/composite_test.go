@@ -0,0 +1,150 @@
+package checks
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestCompositeCheck(t *testing.T) {
+	statuses := api.Statuses{
+		"foo-dns":  {OK: true},
+		"lb1-http": {OK: false},
+		"lb2-http": {OK: true},
+		"bar-tls":  {OK: false},
+	}
+	getStatus := func(name string) (api.Status, bool) {
+		s, ok := statuses[name]
+		return s, ok
+	}
+
+	tests := []struct {
+		name     string
+		config   config.CompositeCheck
+		expected bool
+	}{
+		{
+			name: "all KO",
+			config: config.CompositeCheck{
+				Checks: []string{"foo-dns", "lb1-http"},
+			},
+			expected: false,
+		},
+		{
+			name: "all OK",
+			config: config.CompositeCheck{
+				Checks: []string{"foo-dns", "lb2-http"},
+			},
+			expected: true,
+		},
+		{
+			name: "any OK",
+			config: config.CompositeCheck{
+				Checks: []string{"lb1-http", "lb2-http"},
+				Mode:   "any",
+			},
+			expected: true,
+		},
+		{
+			name: "at least OK",
+			config: config.CompositeCheck{
+				Checks:  []string{"foo-dns", "lb1-http", "lb2-http"},
+				Mode:    "atLeast",
+				AtLeast: 2,
+			},
+			expected: true,
+		},
+		{
+			name: "at least KO",
+			config: config.CompositeCheck{
+				Checks:  []string{"foo-dns", "lb1-http", "bar-tls"},
+				Mode:    "atLeast",
+				AtLeast: 2,
+			},
+			expected: false,
+		},
+		{
+			name: "unknown check",
+			config: config.CompositeCheck{
+				Checks: []string{"foo-dns", "missing-http"},
+			},
+			expected: false,
+		},
+		{
+			name: "expression OK",
+			config: config.CompositeCheck{
+				Expression: "foo-dns && (lb1-http || lb2-http) && !bar-tls",
+			},
+			expected: true,
+		},
+		{
+			name: "expression KO",
+			config: config.CompositeCheck{
+				Expression: "foo-dns && lb1-http || bar-tls",
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCompositeCheck("test", tt.config, getStatus)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ok, err := c.Execute(context.TODO())
+			if ok != tt.expected {
+				t.Errorf("unexpected status, wanted: %t, got: %t (%v)", tt.expected, ok, err)
+			}
+			if ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCompositeInputs(t *testing.T) {
+	getStatus := func(name string) (api.Status, bool) { return api.Status{}, false }
+	tests := []struct {
+		name     string
+		config   config.CompositeCheck
+		expected []string
+	}{
+		{
+			name:     "checks",
+			config:   config.CompositeCheck{Checks: []string{"foo-dns", "lb1-http"}},
+			expected: []string{"foo-dns", "lb1-http"},
+		},
+		{
+			name:     "expression",
+			config:   config.CompositeCheck{Expression: "foo-dns && (lb1-http || !lb2-http) && foo-dns"},
+			expected: []string{"foo-dns", "lb1-http", "lb2-http"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCompositeCheck(tt.name, tt.config, getStatus)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			inputs := c.(Composite).Inputs()
+			if !slices.Equal(inputs, tt.expected) {
+				t.Errorf("unexpected inputs, wanted: %v, got: %v", tt.expected, inputs)
+			}
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	invalid := []string{"", "foo &&", "(foo || bar", "foo bar", "foo & bar", "|| foo"}
+	for _, expr := range invalid {
+		if _, err := parseExpression(expr); err == nil {
+			t.Errorf("expected an error parsing %q", expr)
+		}
+	}
+}
@@ -13,7 +13,10 @@ import (
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
-var _ api.Check = &connCheck{}
+var (
+	_ api.Check    = &connCheck{}
+	_ api.Targeted = &connCheck{}
+)
 
 type connCheck struct {
 	name   string
@@ -26,6 +29,9 @@ func NewConnCheck(name string, config config.ConnCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Address == "" {
 		return nil, fmt.Errorf("address must not be empty")
 	}
@@ -67,6 +73,16 @@ func (c *connCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *connCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *connCheck) Target() string {
+	return hostFromAddress(c.config.Address)
+}
+
 // Execute performs the check
 func (c *connCheck) Execute(ctx context.Context) (bool, error) {
 	if c.dialer == nil {
@@ -13,7 +13,10 @@ import (
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
-var _ api.Check = &dnsCheck{}
+var (
+	_ api.Check    = &dnsCheck{}
+	_ api.Targeted = &dnsCheck{}
+)
 
 type dnsCheck struct {
 	name     string
@@ -27,6 +30,9 @@ func NewDNSCheck(name string, config config.DNSCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Host == "" {
 		return nil, fmt.Errorf("host must not be empty")
 	}
@@ -39,6 +45,9 @@ func NewDNSCheck(name string, config config.DNSCheck) (api.Check, error) {
 	if config.MinRequiredResults == 0 {
 		config.MinRequiredResults = 1
 	}
+	if config.WarningResults != 0 && config.WarningResults <= config.MinRequiredResults {
+		return nil, fmt.Errorf("warningResults must be greater than minRequiredResults")
+	}
 
 	return &dnsCheck{
 		name:   name,
@@ -68,6 +77,16 @@ func (c *dnsCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *dnsCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *dnsCheck) Target() string {
+	return c.config.Host
+}
+
 // Execute performs the check
 func (c *dnsCheck) Execute(ctx context.Context) (bool, error) {
 	if c.resolver == nil {
@@ -81,6 +100,8 @@ func (c *dnsCheck) Execute(ctx context.Context) (bool, error) {
 	ok := len(addrs) >= c.config.MinRequiredResults
 	if !ok {
 		err = fmt.Errorf("insufficient number of results: %d < %d", len(addrs), c.config.MinRequiredResults)
+	} else if len(addrs) < c.config.WarningResults {
+		err = fmt.Errorf("%w: low number of results: %d < %d", api.ErrWarning, len(addrs), c.config.WarningResults)
 	}
 	return ok, err
 }
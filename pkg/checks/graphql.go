@@ -0,0 +1,248 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var (
+	_ api.Check    = &graphqlCheck{}
+	_ api.Targeted = &graphqlCheck{}
+)
+
+// graphqlCheck represents a GraphQL checker
+type graphqlCheck struct {
+	name   string
+	config *config.GraphQLCheck
+	client *http.Client
+}
+
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphqlResponse struct {
+	Data   interface{}    `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+type graphqlError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// ErrorGraphQL is returned when the GraphQL response contains errors
+type ErrorGraphQL struct {
+	messages []string
+}
+
+// Error makes ErrorGraphQL implement the error interface
+func (e ErrorGraphQL) Error() string {
+	return fmt.Sprintf("the response contains %d error(s): %s", len(e.messages), strings.Join(e.messages, "; "))
+}
+
+// NewGraphQLCheck creates a new GraphQL check from the given configuration
+func NewGraphQLCheck(name string, config config.GraphQLCheck) (api.Check, error) {
+	if name == "" {
+		return nil, fmt.Errorf("CheckName must not be empty")
+	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL must not be empty")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, err
+	}
+	if config.Query == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	for _, a := range config.Assertions {
+		if a.Path == "" {
+			return nil, fmt.Errorf("assertion path must not be empty")
+		}
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout = metav1.Duration{Duration: time.Second}
+	}
+	if config.Interval.Duration == 0 {
+		config.Interval = metav1.Duration{Duration: 30 * time.Second}
+	}
+
+	return &graphqlCheck{
+		name:   name,
+		config: &config,
+		client: &http.Client{
+			Timeout: config.Timeout.Duration,
+		},
+	}, nil
+}
+
+func (c *graphqlCheck) Equal(other *graphqlCheck) bool {
+	return c.config.Equal(*other.config)
+}
+
+func (c *graphqlCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.config)
+	if err != nil {
+		return "", "", "", err
+	}
+	return "graphql", c.name, string(b), nil
+}
+
+// Interval indicates how often the check should be performed
+func (c *graphqlCheck) Interval() metav1.Duration {
+	return c.config.Interval
+}
+
+// InitialDelay indicates how long to delay the check start
+func (c *graphqlCheck) InitialDelay() metav1.Duration {
+	return c.config.InitialDelay
+}
+
+// BaseConfig returns the settings common to all checks
+func (c *graphqlCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *graphqlCheck) Target() string {
+	return hostFromURL(c.config.URL)
+}
+
+// Execute performs the check
+func (c *graphqlCheck) Execute(ctx context.Context) (bool, error) {
+	res, err := c.do(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if len(res.Errors) > 0 {
+		e := ErrorGraphQL{}
+		for _, gqlErr := range res.Errors {
+			msg := gqlErr.Message
+			if len(gqlErr.Path) > 0 {
+				msg = fmt.Sprintf("%s (path: %v)", msg, gqlErr.Path)
+			}
+			e.messages = append(e.messages, msg)
+		}
+		return false, e
+	}
+
+	for _, a := range c.config.Assertions {
+		val, err := lookupPath(res.Data, a.Path)
+		if err != nil {
+			return false, err
+		}
+		if val == nil {
+			return false, fmt.Errorf("%s: value is null", a.Path)
+		}
+		if a.Value == "" {
+			continue
+		}
+		if got := stringify(val); got != a.Value {
+			return false, fmt.Errorf("%s: unexpected value %q expected: %q", a.Path, got, a.Value)
+		}
+	}
+
+	return true, nil
+}
+
+// do posts the query to the target URL and decodes the response
+func (c *graphqlCheck) do(ctx context.Context) (*graphqlResponse, error) {
+	body, err := json.Marshal(graphqlRequest{
+		Query:         c.config.Query,
+		OperationName: c.config.OperationName,
+		Variables:     c.config.Variables,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for h, v := range c.config.Headers {
+		req.Header.Set(h, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	res := &graphqlResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(res); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, ErrorUnexpectedStatus{
+				got:      resp.StatusCode,
+				expected: http.StatusOK,
+			}
+		}
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK && len(res.Errors) == 0 {
+		return nil, ErrorUnexpectedStatus{
+			got:      resp.StatusCode,
+			expected: http.StatusOK,
+		}
+	}
+
+	return res, nil
+}
+
+// lookupPath walks the given JSON document following a dot separated path,
+// numeric path elements are used as indexes into arrays
+func lookupPath(doc interface{}, path string) (interface{}, error) {
+	cur := doc
+	for _, key := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]interface{}:
+			val, ok := v[key]
+			if !ok {
+				return nil, fmt.Errorf("%s: %q not found", path, key)
+			}
+			cur = val
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, fmt.Errorf("%s: invalid index %q", path, key)
+			}
+			cur = v[idx]
+		default:
+			return nil, fmt.Errorf("%s: %q not found", path, key)
+		}
+	}
+	return cur, nil
+}
+
+// stringify returns the string representation of a JSON value
+func stringify(val interface{}) string {
+	if s, ok := val.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(b)
+}
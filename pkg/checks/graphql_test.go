@@ -0,0 +1,126 @@
+package checks
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestGraphQLCheck(t *testing.T) {
+	type expected struct {
+		ok  bool
+		err error
+	}
+	tests := []struct {
+		name     string
+		config   config.GraphQLCheck
+		status   int
+		body     string
+		expected expected
+	}{
+		{
+			name: "OK",
+			config: config.GraphQLCheck{
+				URL:   "http://fake.com/graphql",
+				Query: "{ user(id: 1) { id name } }",
+			},
+			status: http.StatusOK,
+			body:   `{"data": {"user": {"id": 1, "name": "foo"}}}`,
+			expected: expected{
+				ok: true,
+			},
+		},
+		{
+			name: "errors with 200",
+			config: config.GraphQLCheck{
+				URL:   "http://fake.com/graphql",
+				Query: "{ user(id: 1) { id name } }",
+			},
+			status: http.StatusOK,
+			body:   `{"data": null, "errors": [{"message": "boom"}]}`,
+			expected: expected{
+				ok: false,
+				err: ErrorGraphQL{
+					messages: []string{"boom"},
+				},
+			},
+		},
+		{
+			name: "assertions OK",
+			config: config.GraphQLCheck{
+				URL:   "http://fake.com/graphql",
+				Query: "query($id: ID!) { user(id: $id) { id roles { name } } }",
+				Variables: map[string]interface{}{
+					"id": 1,
+				},
+				Assertions: []config.DataAssertion{
+					{Path: "user.id", Value: "1"},
+					{Path: "user.roles.0.name", Value: "admin"},
+					{Path: "user.roles"},
+				},
+			},
+			status: http.StatusOK,
+			body:   `{"data": {"user": {"id": 1, "roles": [{"name": "admin"}]}}}`,
+			expected: expected{
+				ok: true,
+			},
+		},
+		{
+			name: "assertion KO",
+			config: config.GraphQLCheck{
+				URL:   "http://fake.com/graphql",
+				Query: "{ user(id: 1) { id email } }",
+				Assertions: []config.DataAssertion{
+					{Path: "user.email"},
+				},
+			},
+			status: http.StatusOK,
+			body:   `{"data": {"user": {"id": 1, "email": null}}}`,
+			expected: expected{
+				ok: false,
+			},
+		},
+		{
+			name: "unexpected status",
+			config: config.GraphQLCheck{
+				URL:   "http://fake.com/graphql",
+				Query: "{ user(id: 1) { id } }",
+			},
+			status: http.StatusBadGateway,
+			body:   "bad gateway",
+			expected: expected{
+				ok: false,
+				err: ErrorUnexpectedStatus{
+					expected: 200,
+					got:      502,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			httpmock.RegisterResponder(http.MethodPost, tt.config.URL, httpmock.NewStringResponder(tt.status, tt.body))
+			c, err := NewGraphQLCheck("test", tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ok, err := c.Execute(context.TODO())
+			if tt.expected.err != nil && (err == nil || err.Error() != tt.expected.err.Error()) {
+				t.Errorf("unexpected response error, wanted: %v, got: %v", tt.expected.err, err)
+			}
+			if tt.expected.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if ok != tt.expected.ok {
+				t.Errorf("unexpected status, wanted: %t, got: %t", tt.expected.ok, ok)
+			}
+		})
+	}
+}
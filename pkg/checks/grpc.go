@@ -24,7 +24,10 @@ import (
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
-var _ api.Check = &grpcCheck{}
+var (
+	_ api.Check    = &grpcCheck{}
+	_ api.Targeted = &grpcCheck{}
+)
 
 type grpcCheck struct {
 	name     string
@@ -70,6 +73,9 @@ func NewGrpcCheck(name string, config config.GRPCCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Address == "" {
 		return nil, fmt.Errorf("address must not be empty")
 	}
@@ -142,9 +148,19 @@ func (c *grpcCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *grpcCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *grpcCheck) Target() string {
+	return hostFromAddress(c.config.Address)
+}
+
 // Execute performs the check
 func (c *grpcCheck) Execute(ctx context.Context) (bool, error) {
-	dialCtx, dialCancel := context.WithTimeout(context.Background(), c.config.ConnTimeout.Duration)
+	dialCtx, dialCancel := withOptionalTimeout(ctx, c.config.ConnTimeout.Duration)
 	defer dialCancel()
 	conn, err := grpc.DialContext(dialCtx, c.config.Address, c.dialOpts...)
 	if err != nil {
@@ -152,7 +168,7 @@ func (c *grpcCheck) Execute(ctx context.Context) (bool, error) {
 	}
 	defer conn.Close()
 
-	rpcCtx, rpcCancel := context.WithTimeout(context.Background(), c.config.RPCTimeout.Duration)
+	rpcCtx, rpcCancel := withOptionalTimeout(ctx, c.config.RPCTimeout.Duration)
 	defer rpcCancel()
 	rpcCtx = metadata.NewOutgoingContext(rpcCtx, c.config.RPCHeaders)
 	resp, err := healthpb.NewHealthClient(conn).Check(rpcCtx,
@@ -0,0 +1,205 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var _ Heartbeat = &heartbeatCheck{}
+
+// HeartbeatEvent represents the kind of ping sent by a job
+type HeartbeatEvent string
+
+const (
+	// HeartbeatSuccess signals that the job completed successfully
+	HeartbeatSuccess HeartbeatEvent = "success"
+	// HeartbeatStart signals that the job has started
+	HeartbeatStart HeartbeatEvent = "start"
+	// HeartbeatFail signals that the job has failed
+	HeartbeatFail HeartbeatEvent = "fail"
+)
+
+// Heartbeat is implemented by passive checks whose status is driven by pings sent by external jobs
+type Heartbeat interface {
+	api.Check
+	// Ping records an event sent by the job being monitored,
+	// the exit code is only meaningful for failures, failures with a warning exit code count as completions
+	Ping(event HeartbeatEvent, exitCode int)
+	// State returns the pings received so far
+	State() HeartbeatState
+	// Restore sets the pings received so far, e.g.: after a restart
+	Restore(state HeartbeatState)
+}
+
+// HeartbeatState holds the pings received by a heartbeat check
+type HeartbeatState struct {
+	Created   time.Time `json:"created"`
+	LastPing  time.Time `json:"lastPing,omitempty"`
+	LastStart time.Time `json:"lastStart,omitempty"`
+	LastFail  time.Time `json:"lastFail,omitempty"`
+	ExitCode  int       `json:"exitCode,omitempty"`
+}
+
+type heartbeatCheck struct {
+	name      string
+	config    *config.HeartbeatCheck
+	created   time.Time
+	lastPing  time.Time
+	lastStart time.Time
+	lastFail  time.Time
+	exitCode  int
+	sync.RWMutex
+}
+
+// NewHeartbeatCheck returns a passive check that fails if the monitored job
+// doesn't ping it within the configured interval plus the grace period
+func NewHeartbeatCheck(name string, config config.HeartbeatCheck) (api.Check, error) {
+	if name == "" {
+		return nil, fmt.Errorf("CheckName must not be empty")
+	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Interval.Duration == 0 {
+		config.Interval = metav1.Duration{Duration: 30 * time.Second}
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout = metav1.Duration{Duration: time.Second}
+	}
+	if config.GracePeriod.Duration == 0 {
+		config.GracePeriod = metav1.Duration{Duration: time.Minute}
+	}
+
+	return &heartbeatCheck{
+		name:    name,
+		config:  &config,
+		created: time.Now(),
+	}, nil
+}
+
+func (c *heartbeatCheck) Equal(other *heartbeatCheck) bool {
+	return c.config.Equal(*other.config)
+}
+
+func (c *heartbeatCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.config)
+	if err != nil {
+		return "", "", "", err
+	}
+	return "heartbeat", c.name, string(b), nil
+}
+
+// Interval indicates how often the pings should be evaluated,
+// at least once per grace period, so that a missed ping or an exceeded run time is reported within the grace period
+func (c *heartbeatCheck) Interval() metav1.Duration {
+	if c.config.GracePeriod.Duration < c.config.Interval.Duration {
+		return c.config.GracePeriod
+	}
+	return c.config.Interval
+}
+
+// InitialDelay indicates how long to delay the check start
+func (c *heartbeatCheck) InitialDelay() metav1.Duration {
+	return c.config.InitialDelay
+}
+
+// BaseConfig returns the settings common to all checks
+func (c *heartbeatCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Ping records an event sent by the job being monitored
+func (c *heartbeatCheck) Ping(event HeartbeatEvent, exitCode int) {
+	c.Lock()
+	defer c.Unlock()
+	now := time.Now()
+	switch event {
+	case HeartbeatStart:
+		c.lastStart = now
+	case HeartbeatFail:
+		if slices.Contains(c.config.WarningExitCodes, exitCode) {
+			c.lastPing = now
+		} else {
+			c.lastFail = now
+		}
+		c.exitCode = exitCode
+		c.lastStart = time.Time{}
+	default:
+		c.lastPing = now
+		c.exitCode = 0
+		c.lastStart = time.Time{}
+	}
+}
+
+// State returns the pings received so far
+func (c *heartbeatCheck) State() HeartbeatState {
+	c.RLock()
+	defer c.RUnlock()
+	return HeartbeatState{
+		Created:   c.created,
+		LastPing:  c.lastPing,
+		LastStart: c.lastStart,
+		LastFail:  c.lastFail,
+		ExitCode:  c.exitCode,
+	}
+}
+
+// Restore sets the pings received so far
+func (c *heartbeatCheck) Restore(state HeartbeatState) {
+	c.Lock()
+	defer c.Unlock()
+	if !state.Created.IsZero() {
+		c.created = state.Created
+	}
+	c.lastPing = state.LastPing
+	c.lastStart = state.LastStart
+	c.lastFail = state.LastFail
+	c.exitCode = state.ExitCode
+}
+
+// Execute evaluates the pings received so far
+func (c *heartbeatCheck) Execute(ctx context.Context) (bool, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.lastFail.After(c.lastPing) {
+		since := humanDuration(time.Since(c.lastFail).Round(time.Second))
+		if c.exitCode != 0 {
+			return false, fmt.Errorf("the job reported a failure %s ago with exit code %d", since, c.exitCode)
+		}
+		return false, fmt.Errorf("the job reported a failure %s ago", since)
+	}
+
+	if c.config.MaxRunTime.Duration != 0 && !c.lastStart.IsZero() {
+		if runTime := time.Since(c.lastStart); runTime > c.config.MaxRunTime.Duration {
+			return false, fmt.Errorf("the job started %s ago and hasn't completed yet", humanDuration(runTime.Round(time.Second)))
+		}
+	}
+
+	last := c.lastPing
+	if last.IsZero() {
+		last = c.created
+	}
+	if since := time.Since(last); since > c.config.Interval.Duration+c.config.GracePeriod.Duration {
+		if c.lastPing.IsZero() {
+			return false, fmt.Errorf("no ping received since the check was created %s ago", humanDuration(since.Round(time.Second)))
+		}
+		return false, fmt.Errorf("no ping received in the last %s", humanDuration(since.Round(time.Second)))
+	}
+
+	if c.exitCode != 0 && slices.Contains(c.config.WarningExitCodes, c.exitCode) {
+		since := humanDuration(time.Since(c.lastPing).Round(time.Second))
+		return true, fmt.Errorf("%w: the job completed %s ago with exit code %d", api.ErrWarning, since, c.exitCode)
+	}
+
+	return true, nil
+}
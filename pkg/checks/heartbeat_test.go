@@ -0,0 +1,128 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestHeartbeatCheck(t *testing.T) {
+	type ping struct {
+		event    HeartbeatEvent
+		exitCode int
+	}
+	tests := []struct {
+		name     string
+		config   config.HeartbeatCheck
+		pings    []ping
+		wait     time.Duration
+		expected bool
+		warning  bool
+	}{
+		{
+			name: "within interval",
+			config: config.HeartbeatCheck{
+				BaseCheck: config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			expected: true,
+		},
+		{
+			name: "missed ping",
+			config: config.HeartbeatCheck{
+				GracePeriod: metav1.Duration{Duration: time.Millisecond},
+				BaseCheck:   config.BaseCheck{Interval: metav1.Duration{Duration: time.Millisecond}},
+			},
+			wait:     10 * time.Millisecond,
+			expected: false,
+		},
+		{
+			name: "reported failure",
+			config: config.HeartbeatCheck{
+				BaseCheck: config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			pings:    []ping{{event: HeartbeatSuccess}, {event: HeartbeatFail, exitCode: 2}},
+			expected: false,
+		},
+		{
+			name: "recovered",
+			config: config.HeartbeatCheck{
+				BaseCheck: config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			pings:    []ping{{event: HeartbeatFail}, {event: HeartbeatStart}, {event: HeartbeatSuccess}},
+			expected: true,
+		},
+		{
+			name: "run time exceeded",
+			config: config.HeartbeatCheck{
+				MaxRunTime: metav1.Duration{Duration: time.Millisecond},
+				BaseCheck:  config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			pings:    []ping{{event: HeartbeatStart}},
+			wait:     10 * time.Millisecond,
+			expected: false,
+		},
+		{
+			name: "warning exit code",
+			config: config.HeartbeatCheck{
+				WarningExitCodes: []int{1},
+				BaseCheck:        config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			pings:    []ping{{event: HeartbeatFail, exitCode: 1}},
+			expected: true,
+			warning:  true,
+		},
+		{
+			name: "recovered from warning",
+			config: config.HeartbeatCheck{
+				WarningExitCodes: []int{1},
+				BaseCheck:        config.BaseCheck{Interval: metav1.Duration{Duration: time.Hour}},
+			},
+			pings:    []ping{{event: HeartbeatFail, exitCode: 1}, {event: HeartbeatSuccess}},
+			expected: true,
+		},
+		{
+			name: "missed ping after warning",
+			config: config.HeartbeatCheck{
+				WarningExitCodes: []int{1},
+				GracePeriod:      metav1.Duration{Duration: time.Millisecond},
+				BaseCheck:        config.BaseCheck{Interval: metav1.Duration{Duration: time.Millisecond}},
+			},
+			pings:    []ping{{event: HeartbeatFail, exitCode: 1}},
+			wait:     10 * time.Millisecond,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewHeartbeatCheck("test", tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			hb, ok := c.(Heartbeat)
+			if !ok {
+				t.Fatalf("heartbeat check doesn't implement the Heartbeat interface")
+			}
+			for _, p := range tt.pings {
+				hb.Ping(p.event, p.exitCode)
+			}
+			time.Sleep(tt.wait)
+			ok, err = c.Execute(context.TODO())
+			if ok != tt.expected {
+				t.Errorf("unexpected status, wanted: %t, got: %t (%v)", tt.expected, ok, err)
+			}
+			if warning := errors.Is(err, api.ErrWarning); warning != tt.warning {
+				t.Errorf("unexpected warning, wanted: %t, got: %t (%v)", tt.warning, warning, err)
+			}
+			if ok && !tt.warning && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
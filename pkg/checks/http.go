@@ -16,7 +16,10 @@ import (
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
-var _ api.Check = &httpCheck{}
+var (
+	_ api.Check    = &httpCheck{}
+	_ api.Targeted = &httpCheck{}
+)
 
 // httpCheck represents an http checker
 type httpCheck struct {
@@ -58,6 +61,9 @@ func NewHTTPCheck(name string, config config.HTTPCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 
 	if config.ExpectedStatus == 0 {
 		config.ExpectedStatus = http.StatusOK
@@ -105,6 +111,16 @@ func (c *httpCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *httpCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *httpCheck) Target() string {
+	return hostFromURL(c.config.URL)
+}
+
 // Execute performs the check
 func (c *httpCheck) Execute(ctx context.Context) (bool, error) {
 	resp, err := c.do(ctx)
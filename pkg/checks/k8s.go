@@ -36,6 +36,9 @@ func NewK8sCheck(name string, config config.K8sCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Interval.Duration == 0 {
 		config.Interval = metav1.Duration{Duration: 30 * time.Second}
 	}
@@ -84,6 +87,11 @@ func (c *k8sCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *k8sCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
 // Interval indicates how often the check should be performed
 func (c *k8sCheck) Execute(ctx context.Context) (bool, error) {
 	ul, err := c.do(ctx)
@@ -142,7 +150,7 @@ func (c *k8sCheck) do(ctx context.Context) (*unstructured.UnstructuredList, erro
 			Kind:    gvk.Kind,
 			Version: gvk.Version,
 		})
-		if err := c.client.Get(context.Background(), client.ObjectKey{
+		if err := c.client.Get(ctx, client.ObjectKey{
 			Namespace: c.config.Namespace,
 			Name:      c.config.Name,
 		}, &u); err != nil {
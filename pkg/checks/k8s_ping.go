@@ -30,6 +30,9 @@ func NewK8sPing(name string, config config.K8sPing) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Protocol == "" {
 		config.Protocol = "tcp"
 	}
@@ -81,6 +84,11 @@ func (c *k8sPinger) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *k8sPinger) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
 func (c *k8sPinger) Execute(ctx context.Context) (bool, error) {
 	if c.dialer == nil {
 		c.dialer = &net.Dialer{
@@ -0,0 +1,177 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var (
+	_ api.Check    = &promQueryCheck{}
+	_ api.Targeted = &promQueryCheck{}
+)
+
+var operators = map[string]func(a, b float64) bool{
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+}
+
+type promQueryCheck struct {
+	name    string
+	config  *config.PromQueryCheck
+	client  promv1.API
+	compare func(a, b float64) bool
+}
+
+// headerRoundTripper sets the configured headers on every request
+type headerRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for h, v := range rt.headers {
+		req.Header.Set(h, v)
+	}
+	return rt.next.RoundTrip(req)
+}
+
+// NewPromQueryCheck returns a check that evaluates a PromQL expression
+// and compares the result with the configured threshold
+func NewPromQueryCheck(name string, config config.PromQueryCheck) (api.Check, error) {
+	if name == "" {
+		return nil, fmt.Errorf("CheckName must not be empty")
+	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL must not be empty")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, err
+	}
+	if config.Query == "" {
+		return nil, fmt.Errorf("query must not be empty")
+	}
+	if config.Operator == "" {
+		config.Operator = "<"
+	}
+	compare, ok := operators[config.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unknown operator %q", config.Operator)
+	}
+	if config.Interval.Duration == 0 {
+		config.Interval = metav1.Duration{Duration: 30 * time.Second}
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout = metav1.Duration{Duration: time.Second}
+	}
+
+	client, err := promapi.NewClient(promapi.Config{
+		Address: config.URL,
+		Client: &http.Client{
+			Timeout: config.Timeout.Duration,
+			Transport: &headerRoundTripper{
+				headers: config.Headers,
+				next:    promapi.DefaultRoundTripper,
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return &promQueryCheck{
+		name:    name,
+		config:  &config,
+		client:  promv1.NewAPI(client),
+		compare: compare,
+	}, nil
+}
+
+func (c *promQueryCheck) Equal(other *promQueryCheck) bool {
+	return c.config.Equal(*other.config)
+}
+
+func (c *promQueryCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.config)
+	if err != nil {
+		return "", "", "", err
+	}
+	return "promquery", c.name, string(b), nil
+}
+
+// Interval indicates how often the check should be performed
+func (c *promQueryCheck) Interval() metav1.Duration {
+	return c.config.Interval
+}
+
+// InitialDelay indicates how long to delay the check start
+func (c *promQueryCheck) InitialDelay() metav1.Duration {
+	return c.config.InitialDelay
+}
+
+// BaseConfig returns the settings common to all checks
+func (c *promQueryCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *promQueryCheck) Target() string {
+	return hostFromURL(c.config.URL)
+}
+
+// Execute performs the check
+func (c *promQueryCheck) Execute(ctx context.Context) (bool, error) {
+	res, _, err := c.client.Query(ctx, c.config.Query, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("query failed: %w", err)
+	}
+
+	var samples model.Vector
+	switch v := res.(type) {
+	case *model.Scalar:
+		samples = append(samples, &model.Sample{Value: v.Value, Timestamp: v.Timestamp})
+	case model.Vector:
+		samples = v
+	default:
+		return false, fmt.Errorf("unsupported result type %q, the query must return a scalar or an instant vector", res.Type())
+	}
+
+	if len(samples) == 0 {
+		if c.config.NoDataOK {
+			return true, nil
+		}
+		return false, fmt.Errorf("the query returned no data")
+	}
+
+	var offending []string
+	for _, s := range samples {
+		if !c.compare(float64(s.Value), c.config.Threshold) {
+			offending = append(offending, fmt.Sprintf("%s => %s", s.Metric, s.Value))
+		}
+	}
+	if len(offending) > 0 {
+		return false, fmt.Errorf("%d of %d samples don't satisfy the condition '%s %v': %s", len(offending), len(samples), c.config.Operator, c.config.Threshold, strings.Join(offending, ", "))
+	}
+
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestPromQueryCheck(t *testing.T) {
+	results := map[string]string{
+		"scalar(queue_depth)": `{"resultType": "scalar", "result": [1672531200, "42"]}`,
+		"error_ratio":         `{"resultType": "vector", "result": [{"metric": {"job": "api"}, "value": [1672531200, "0.01"]}, {"metric": {"job": "web"}, "value": [1672531200, "0.2"]}]}`,
+		"absent_metric":       `{"resultType": "vector", "result": []}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = r.ParseForm()
+		data, ok := results[r.Form.Get("query")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status": "error", "errorType": "bad_data", "error": "parse error"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status": "success", "data": %s}`, data)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		config    config.PromQueryCheck
+		ok        bool
+		errSubstr string
+	}{
+		{
+			name: "scalar OK",
+			config: config.PromQueryCheck{
+				Query:     "scalar(queue_depth)",
+				Threshold: 100,
+			},
+			ok: true,
+		},
+		{
+			name: "scalar KO",
+			config: config.PromQueryCheck{
+				Query:     "scalar(queue_depth)",
+				Operator:  ">=",
+				Threshold: 100,
+			},
+			ok: false,
+		},
+		{
+			name: "vector KO",
+			config: config.PromQueryCheck{
+				Query:     "error_ratio",
+				Operator:  "<=",
+				Threshold: 0.05,
+			},
+			ok:        false,
+			errSubstr: `{job="web"}`,
+		},
+		{
+			name: "no data",
+			config: config.PromQueryCheck{
+				Query: "absent_metric",
+			},
+			ok:        false,
+			errSubstr: "no data",
+		},
+		{
+			name: "no data OK",
+			config: config.PromQueryCheck{
+				Query:    "absent_metric",
+				NoDataOK: true,
+			},
+			ok: true,
+		},
+		{
+			name: "query error",
+			config: config.PromQueryCheck{
+				Query: "bad(",
+			},
+			ok:        false,
+			errSubstr: "parse error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.URL = srv.URL
+			c, err := NewPromQueryCheck("test", tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ok, err := c.Execute(context.TODO())
+			if ok != tt.ok {
+				t.Errorf("unexpected status, wanted: %t, got: %t (%v)", tt.ok, ok, err)
+			}
+			if tt.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.errSubstr != "" && (err == nil || !strings.Contains(err.Error(), tt.errSubstr)) {
+				t.Errorf("unexpected error, wanted it to contain %q, got: %v", tt.errSubstr, err)
+			}
+		})
+	}
+}
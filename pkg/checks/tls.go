@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -14,7 +15,10 @@ import (
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 )
 
-var _ api.Check = &tlsCheck{}
+var (
+	_ api.Check    = &tlsCheck{}
+	_ api.Targeted = &tlsCheck{}
+)
 
 type tlsCheck struct {
 	name    string
@@ -29,6 +33,9 @@ func NewTLSCheck(name string, config config.TLSCheck) (api.Check, error) {
 	if name == "" {
 		return nil, fmt.Errorf("CheckName must not be empty")
 	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Address == "" {
 		return nil, fmt.Errorf("address must not be empty")
 	}
@@ -80,13 +87,30 @@ func (c *tlsCheck) InitialDelay() metav1.Duration {
 	return c.config.InitialDelay
 }
 
+// BaseConfig returns the settings common to all checks
+func (c *tlsCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *tlsCheck) Target() string {
+	return hostFromAddress(c.config.Address)
+}
+
 // Execute performs the check
 func (c *tlsCheck) Execute(ctx context.Context) (bool, error) {
-	conn, err := tls.Dial("tcp", c.config.Address, c.tlsOpts)
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{
+			Timeout: c.config.Timeout.Duration,
+		},
+		Config: c.tlsOpts,
+	}
+	rawConn, err := dialer.DialContext(ctx, "tcp", c.config.Address)
 	if err != nil {
 		return false, fmt.Errorf("failed to connect: %w", err)
 	}
-	defer conn.Close()
+	defer rawConn.Close()
+	conn := rawConn.(*tls.Conn)
 
 	for _, hostName := range c.config.HostNames {
 		if c.config.SkipChainValidation {
@@ -107,6 +131,9 @@ func (c *tlsCheck) Execute(ctx context.Context) (bool, error) {
 	if ttl <= c.config.ExpiryThreshold.Duration {
 		return false, fmt.Errorf("the certificate will expire in %s", humanDuration(ttl))
 	}
+	if c.config.WarningThreshold.Duration > 0 && ttl <= c.config.WarningThreshold.Duration {
+		return true, fmt.Errorf("%w: the certificate will expire in %s", api.ErrWarning, humanDuration(ttl))
+	}
 
 	// certs := conn.ConnectionState().PeerCertificates
 	// for _, cert := range certs {
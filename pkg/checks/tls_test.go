@@ -0,0 +1,77 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestTLSCheck(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	expiry := srv.Certificate().NotAfter
+
+	type expected struct {
+		ok      bool
+		warning bool
+	}
+	tests := []struct {
+		name             string
+		warningThreshold time.Duration
+		expiryThreshold  time.Duration
+		expected         expected
+	}{
+		{
+			name:     "OK without a warning threshold",
+			expected: expected{ok: true},
+		},
+		{
+			name:             "OK before the warning threshold",
+			warningThreshold: time.Hour,
+			expected:         expected{ok: true},
+		},
+		{
+			name:             "warning",
+			warningThreshold: time.Until(expiry) + time.Hour,
+			expected:         expected{ok: true, warning: true},
+		},
+		{
+			name:            "KO",
+			expiryThreshold: time.Until(expiry) + time.Hour,
+			expected:        expected{ok: false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewTLSCheck(tt.name, config.TLSCheck{
+				Address:             srv.Listener.Addr().String(),
+				InsecureSkipVerify:  true,
+				SkipChainValidation: true,
+				ExpiryThreshold:     metav1.Duration{Duration: tt.expiryThreshold},
+				WarningThreshold:    metav1.Duration{Duration: tt.warningThreshold},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ok, err := c.Execute(context.TODO())
+			if ok != tt.expected.ok {
+				t.Errorf("unexpected status, wanted: %t, got: %t (%v)", tt.expected.ok, ok, err)
+			}
+			if warning := errors.Is(err, api.ErrWarning); warning != tt.expected.warning {
+				t.Errorf("unexpected warning, wanted: %t, got: %v", tt.expected.warning, err)
+			}
+			if tt.expected.ok && !tt.expected.warning && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
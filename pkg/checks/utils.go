@@ -1,7 +1,10 @@
 package checks
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -30,3 +33,30 @@ func humanDuration(t time.Duration) string {
 
 	return b.String()
 }
+
+// hostFromURL returns the host name of the given URL
+func hostFromURL(u string) string {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	return parsed.Hostname()
+}
+
+// hostFromAddress returns the host part of the given address, it may or may not include a port
+func hostFromAddress(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// withOptionalTimeout derives a context with the given timeout from the parent context,
+// if the timeout is not set, the parent's deadline, if any, still applies
+func withOptionalTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, timeout)
+}
@@ -0,0 +1,178 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var (
+	_ api.Check    = &wsCheck{}
+	_ api.Targeted = &wsCheck{}
+)
+
+// wsCheck represents a WebSocket checker
+type wsCheck struct {
+	name   string
+	config *config.WebSocketCheck
+	dialer *websocket.Dialer
+}
+
+// ErrorUnexpectedMessage is returned when the service being checked replies with an unexpected message
+type ErrorUnexpectedMessage struct {
+	expected string
+	got      string
+}
+
+// Error makes ErrorUnexpectedMessage implement the error interface
+func (e ErrorUnexpectedMessage) Error() string {
+	return fmt.Sprintf("message %q does not contain expected content %q", e.got, e.expected)
+}
+
+// NewWebSocketCheck creates a new WebSocket check from the given configuration
+func NewWebSocketCheck(name string, config config.WebSocketCheck) (api.Check, error) {
+	if name == "" {
+		return nil, fmt.Errorf("CheckName must not be empty")
+	}
+	if err := config.BaseCheck.Validate(); err != nil {
+		return nil, err
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL must not be empty")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "ws" && u.Scheme != "wss" {
+		return nil, fmt.Errorf("unsupported URL scheme %q, must be one of ws or wss", u.Scheme)
+	}
+	if config.Timeout.Duration == 0 {
+		config.Timeout = metav1.Duration{Duration: time.Second}
+	}
+	if config.Interval.Duration == 0 {
+		config.Interval = metav1.Duration{Duration: 30 * time.Second}
+	}
+
+	return &wsCheck{
+		name:   name,
+		config: &config,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: config.Timeout.Duration,
+			Subprotocols:     config.Subprotocols,
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: config.InsecureSkipVerify,
+			},
+		},
+	}, nil
+}
+
+func (c *wsCheck) Equal(other *wsCheck) bool {
+	return c.config.Equal(*other.config)
+}
+
+func (c *wsCheck) Config() (string, string, string, error) {
+	b, err := json.Marshal(c.config)
+	if err != nil {
+		return "", "", "", err
+	}
+	return "websocket", c.name, string(b), nil
+}
+
+// Interval indicates how often the check should be performed
+func (c *wsCheck) Interval() metav1.Duration {
+	return c.config.Interval
+}
+
+// InitialDelay indicates how long to delay the check start
+func (c *wsCheck) InitialDelay() metav1.Duration {
+	return c.config.InitialDelay
+}
+
+// BaseConfig returns the settings common to all checks
+func (c *wsCheck) BaseConfig() config.BaseCheck {
+	return c.config.BaseCheck
+}
+
+// Target returns the host being checked
+func (c *wsCheck) Target() string {
+	return hostFromURL(c.config.URL)
+}
+
+// Execute performs the check
+func (c *wsCheck) Execute(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout.Duration)
+	defer cancel()
+
+	headers := make(http.Header)
+	for h, v := range c.config.Headers {
+		headers.Add(h, v)
+	}
+
+	conn, resp, err := c.dialer.DialContext(ctx, c.config.URL, headers)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		if resp != nil {
+			return false, fmt.Errorf("handshake failed with status code '%v': %w", resp.StatusCode, err)
+		}
+		return false, fmt.Errorf("failed to connect: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if len(c.config.Subprotocols) > 0 && conn.Subprotocol() == "" {
+		return false, fmt.Errorf("the server did not accept any of the requested subprotocols: %s", strings.Join(c.config.Subprotocols, ", "))
+	}
+
+	if c.config.CertExpiryThreshold.Duration != 0 {
+		if tlsConn, ok := conn.UnderlyingConn().(*tls.Conn); ok {
+			ttl := time.Until(tlsConn.ConnectionState().PeerCertificates[0].NotAfter)
+			if ttl <= c.config.CertExpiryThreshold.Duration {
+				return false, fmt.Errorf("the certificate will expire in %s", humanDuration(ttl))
+			}
+		}
+	}
+
+	deadline, _ := ctx.Deadline()
+	if c.config.Message != "" {
+		msgType := websocket.TextMessage
+		if c.config.Binary {
+			msgType = websocket.BinaryMessage
+		}
+		_ = conn.SetWriteDeadline(deadline)
+		if err := conn.WriteMessage(msgType, []byte(c.config.Message)); err != nil {
+			return false, fmt.Errorf("failed to send message: %w", err)
+		}
+	}
+
+	if c.config.ExpectedMessage != "" {
+		_ = conn.SetReadDeadline(deadline)
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return false, fmt.Errorf("failed to read message: %w", err)
+		}
+		if !strings.Contains(string(msg), c.config.ExpectedMessage) {
+			return false, ErrorUnexpectedMessage{
+				got:      string(msg),
+				expected: c.config.ExpectedMessage,
+			}
+		}
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), deadline)
+
+	return true, nil
+}
@@ -0,0 +1,128 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestWebSocketCheck(t *testing.T) {
+	upgrader := websocket.Upgrader{
+		Subprotocols: []string{"echo"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		mt, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(mt, append([]byte("echo: "), msg...))
+	}))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	type expected struct {
+		ok  bool
+		err error
+	}
+	tests := []struct {
+		name     string
+		config   config.WebSocketCheck
+		expected expected
+	}{
+		{
+			name: "handshake OK",
+			config: config.WebSocketCheck{
+				URL:     wsURL,
+				Headers: map[string]string{"Authorization": "secret"},
+			},
+			expected: expected{
+				ok: true,
+			},
+		},
+		{
+			name: "handshake KO",
+			config: config.WebSocketCheck{
+				URL: wsURL,
+			},
+			expected: expected{
+				ok: false,
+			},
+		},
+		{
+			name: "message OK",
+			config: config.WebSocketCheck{
+				URL:             wsURL,
+				Headers:         map[string]string{"Authorization": "secret"},
+				Subprotocols:    []string{"echo"},
+				Message:         "ping",
+				ExpectedMessage: "echo: ping",
+			},
+			expected: expected{
+				ok: true,
+			},
+		},
+		{
+			name: "unexpected message",
+			config: config.WebSocketCheck{
+				URL:             wsURL,
+				Headers:         map[string]string{"Authorization": "secret"},
+				Message:         "ping",
+				Binary:          true,
+				ExpectedMessage: "pong",
+			},
+			expected: expected{
+				ok: false,
+				err: ErrorUnexpectedMessage{
+					expected: "pong",
+					got:      "echo: ping",
+				},
+			},
+		},
+		{
+			name: "unsupported subprotocol",
+			config: config.WebSocketCheck{
+				URL:          wsURL,
+				Headers:      map[string]string{"Authorization": "secret"},
+				Subprotocols: []string{"chat"},
+			},
+			expected: expected{
+				ok: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWebSocketCheck("test", tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ok, err := c.Execute(context.TODO())
+			if tt.expected.err != nil && !errors.Is(err, tt.expected.err) {
+				t.Errorf("unexpected response error, wanted: %v, got: %v", tt.expected.err, err)
+			}
+			if tt.expected.ok && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if ok != tt.expected.ok {
+				t.Errorf("unexpected status, wanted: %t, got: %t", tt.expected.ok, ok)
+			}
+		})
+	}
+}
@@ -1,14 +1,22 @@
 package checksapi
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"golang.org/x/exp/slices"
 	"sigs.k8s.io/yaml"
 
+	"github.com/luisdavim/synthetic-checker/pkg/api"
 	"github.com/luisdavim/synthetic-checker/pkg/checker"
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
 	"github.com/luisdavim/synthetic-checker/pkg/config"
 	"github.com/luisdavim/synthetic-checker/pkg/server"
 )
@@ -17,20 +25,44 @@ const cfgTpl = `{"%sChecks": {%q: %s}}`
 
 func statusHandler(chkr *checker.Runner, srv *server.Server, failStatus, degradedStatus int) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		statusCode := http.StatusOK
+		sel, err := parseSelector(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		checkStatus := chkr.GetStatus()
-		if failStatus != http.StatusOK || degradedStatus != http.StatusOK {
-			allFailed, anyFailed := checkStatus.Evaluate()
-			if allFailed {
-				statusCode = failStatus
-			} else if anyFailed {
-				statusCode = degradedStatus
-			}
+		if len(sel.Types) > 0 || len(sel.Labels) > 0 {
+			checkStatus = checker.FilterStatuses(checkStatus, sel)
 		}
-		srv.JSONResponse(w, r, checkStatus, statusCode)
+		statusResponse(srv, w, r, checkStatus, chkr.GetEvaluation(), failStatus, degradedStatus)
 	}
 }
 
+// statusResponse evaluates the given statuses and responds with the matching status code,
+// if the summary query parameter is set, the statuses are returned along with their summary
+func statusResponse(srv *server.Server, w http.ResponseWriter, r *http.Request, checkStatus api.Statuses, policy config.EvaluationPolicy, failStatus, degradedStatus int) {
+	summary := checkStatus.Summarize(policy)
+	statusCode := http.StatusOK
+	switch summary.Status {
+	case api.HealthFailed:
+		statusCode = failStatus
+	case api.HealthDegraded:
+		statusCode = degradedStatus
+	}
+	if v := r.URL.Query().Get("summary"); v != "" {
+		withSummary, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if withSummary {
+			srv.JSONResponse(w, r, api.Report{Summary: summary, Checks: checkStatus}, statusCode)
+			return
+		}
+	}
+	srv.JSONResponse(w, r, checkStatus, statusCode)
+}
+
 func checkHandler(chkr *checker.Runner) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
@@ -57,6 +89,10 @@ func checkHandler(chkr *checker.Runner) http.HandlerFunc {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if err := chkr.Persist(vars["name"]+"-"+vars["type"], cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 }
 
@@ -67,6 +103,226 @@ func deleteCheckHandler(chkr *checker.Runner) http.HandlerFunc {
 	}
 }
 
+func heartbeatHandler(chkr *checker.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		event := checks.HeartbeatSuccess
+		exitCode := 0
+		switch e := vars["event"]; e {
+		case "":
+		case string(checks.HeartbeatStart):
+			event = checks.HeartbeatStart
+		case string(checks.HeartbeatFail):
+			event = checks.HeartbeatFail
+		default:
+			var err error
+			exitCode, err = strconv.Atoi(e)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid heartbeat event %q", e), http.StatusBadRequest)
+				return
+			}
+			if exitCode != 0 {
+				event = checks.HeartbeatFail
+			}
+		}
+		if err := chkr.Heartbeat(r.Context(), vars["name"]+"-heartbeat", event, exitCode); err != nil {
+			statusCode := http.StatusBadRequest
+			if errors.Is(err, checker.ErrCheckNotFound) {
+				statusCode = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), statusCode)
+			return
+		}
+	}
+}
+
+func historyHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+		history, ok := chkr.GetHistoryFor(name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("%v: %s", checker.ErrCheckNotFound, name), http.StatusNotFound)
+			return
+		}
+		srv.JSONResponse(w, r, history, http.StatusOK)
+	}
+}
+
+func sloHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+		if !chkr.HasCheck(name) {
+			http.Error(w, fmt.Sprintf("%v: %s", checker.ErrCheckNotFound, name), http.StatusNotFound)
+			return
+		}
+		slo, ok := chkr.GetSLOFor(name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("no SLO configured for %s", name), http.StatusNotFound)
+			return
+		}
+		srv.JSONResponse(w, r, slo, http.StatusOK)
+	}
+}
+
+// parseSelector reads the check types and labels, in the "key=value" format, from the query string
+func parseSelector(r *http.Request) (checker.Selector, error) {
+	query := r.URL.Query()
+	labels, err := checker.ParseLabelSelector(strings.Join(query["label"], ","))
+	if err != nil {
+		return checker.Selector{}, err
+	}
+	return checker.Selector{
+		Types:  query["type"],
+		Labels: labels,
+	}, nil
+}
+
+// runChecks executes the given checks, if the wait query parameter is set, it waits for and returns the fresh statuses,
+// otherwise, the checks are executed in the background
+func runChecks(chkr *checker.Runner, srv *server.Server, w http.ResponseWriter, r *http.Request, names []string) {
+	wait := false
+	if v := r.URL.Query().Get("wait"); v != "" {
+		var err error
+		if wait, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid wait parameter %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if !wait {
+		go func() {
+			_, _ = chkr.RunChecks(context.Background(), names)
+		}()
+		srv.JSONResponse(w, r, names, http.StatusAccepted)
+		return
+	}
+	statuses, err := chkr.RunChecks(r.Context(), names)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, checker.ErrInformOnly) {
+			statusCode = http.StatusConflict
+		}
+		http.Error(w, err.Error(), statusCode)
+		return
+	}
+	srv.JSONResponse(w, r, statuses, http.StatusOK)
+}
+
+func runHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["name"]
+		if !chkr.HasCheck(name) {
+			http.Error(w, fmt.Sprintf("%v: %s", checker.ErrCheckNotFound, name), http.StatusNotFound)
+			return
+		}
+		runChecks(chkr, srv, w, r, []string{name})
+	}
+}
+
+func bulkRunHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sel, err := parseSelector(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		runChecks(chkr, srv, w, r, chkr.Select(sel))
+	}
+}
+
+// eventsHandler streams the runner events as server-sent events,
+// the events can be filtered by check name and event type with the name and type query parameters, which can be repeated
+func eventsHandler(chkr *checker.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+		query := r.URL.Query()
+		names, types := query["name"], query["type"]
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for event := range chkr.Events(r.Context()) {
+			if len(names) > 0 && !slices.Contains(names, event.Name) {
+				continue
+			}
+			if len(types) > 0 && !slices.Contains(types, string(event.Type)) {
+				continue
+			}
+			b, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", b); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func groupsHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		srv.JSONResponse(w, r, chkr.GetGroups(), http.StatusOK)
+	}
+}
+
+// groupHandler returns the statuses of the checks in the given group,
+// the status code is based on the group's evaluation policy and the group's status codes, if set
+func groupHandler(chkr *checker.Runner, srv *server.Server, failStatus, degradedStatus int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checkStatus, group, err := chkr.GetGroupStatus(mux.Vars(r)["group"])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if group.FailedStatusCode != 0 {
+			failStatus = group.FailedStatusCode
+		}
+		if group.DegradedStatusCode != 0 {
+			degradedStatus = group.DegradedStatusCode
+		}
+		statusResponse(srv, w, r, checkStatus, group.Evaluation, failStatus, degradedStatus)
+	}
+}
+
+func silencesHandler(chkr *checker.Runner, srv *server.Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		srv.JSONResponse(w, r, chkr.GetSilences(), http.StatusOK)
+	}
+}
+
+func silenceHandler(chkr *checker.Runner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		if r.Method == http.MethodDelete {
+			if err := chkr.DelSilence(vars["name"]); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+			}
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var silence config.Silence
+		if err := yaml.Unmarshal(b, &silence); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := chkr.AddSilence(vars["name"], silence); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+}
+
 func setRoutes(chkr *checker.Runner, srv *server.Server, failStatus, degradedStatus int) {
 	routes := server.Routes{
 		"/": {
@@ -74,7 +330,8 @@ func setRoutes(chkr *checker.Runner, srv *server.Server, failStatus, degradedSta
 			Methods: []string{http.MethodGet},
 			Name:    "status",
 		},
-		"/checks/{type}/{name}": {
+		// check types never contain dashes, this avoids clashing with the routes using check names, e.g.: /checks/example-http/run
+		"/checks/{type:[a-zA-Z0-9]+}/{name}": {
 			Func:    checkHandler(chkr),
 			Methods: []string{http.MethodPost, http.MethodPut, http.MethodDelete},
 			Name:    "add",
@@ -84,6 +341,63 @@ func setRoutes(chkr *checker.Runner, srv *server.Server, failStatus, degradedSta
 			Methods: []string{http.MethodDelete},
 			Name:    "delete",
 		},
+		"/checks/run": {
+			Func:    bulkRunHandler(chkr, srv),
+			Methods: []string{http.MethodPost},
+			Name:    "bulkRun",
+		},
+		"/checks/{name}/run": {
+			Func:    runHandler(chkr, srv),
+			Methods: []string{http.MethodPost},
+			Name:    "run",
+		},
+		"/checks/{name}/history": {
+			Func:    historyHandler(chkr, srv),
+			Methods: []string{http.MethodGet},
+			Name:    "history",
+		},
+		"/checks/{name}/slo": {
+			Func:    sloHandler(chkr, srv),
+			Methods: []string{http.MethodGet},
+			Name:    "slo",
+		},
+		"/heartbeats/{name}": {
+			Func:    heartbeatHandler(chkr),
+			Methods: []string{http.MethodPost},
+			Name:    "heartbeat",
+		},
+		"/heartbeats/{name}/{event}": {
+			Func:    heartbeatHandler(chkr),
+			Methods: []string{http.MethodPost},
+			Name:    "heartbeatEvent",
+		},
+		"/events": {
+			Func:    eventsHandler(chkr),
+			Methods: []string{http.MethodGet},
+			Name:    "events",
+			// the request duration of the event streams is meaningless
+			NoInstrumentation: true,
+		},
+		"/groups": {
+			Func:    groupsHandler(chkr, srv),
+			Methods: []string{http.MethodGet},
+			Name:    "groups",
+		},
+		"/groups/{group}": {
+			Func:    groupHandler(chkr, srv, failStatus, degradedStatus),
+			Methods: []string{http.MethodGet},
+			Name:    "group",
+		},
+		"/silences": {
+			Func:    silencesHandler(chkr, srv),
+			Methods: []string{http.MethodGet},
+			Name:    "silences",
+		},
+		"/silences/{name}": {
+			Func:    silenceHandler(chkr),
+			Methods: []string{http.MethodPost, http.MethodPut, http.MethodDelete},
+			Name:    "silence",
+		},
 	}
 	srv.WithRoutes(routes)
 }
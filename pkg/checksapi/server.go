@@ -14,7 +14,7 @@ func New(chkr *checker.Runner, srvCfg server.Config, failStatus, degradedStatus
 		// ensure the checker routines are stopped
 		chkr.Stop()
 		time.Sleep(2 * time.Second)
-		return nil
+		return chkr.Close()
 	})
 	setRoutes(chkr, srv, failStatus, degradedStatus)
 
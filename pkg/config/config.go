@@ -1,20 +1,35 @@
 package config
 
 import (
+	"time"
+
 	"google.golang.org/grpc/metadata"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Config represents the checks configuration
 type Config struct {
-	Informer   InformerCfg          `mapstructure:"informer,omitempty"`
-	HTTPChecks map[string]HTTPCheck `mapstructure:"httpChecks"`
-	GRPCChecks map[string]GRPCCheck `mapstructure:"grpcChecks"`
-	DNSChecks  map[string]DNSCheck  `mapstructure:"dnsChecks"`
-	ConnChecks map[string]ConnCheck `mapstructure:"connChecks"`
-	TLSChecks  map[string]TLSCheck  `mapstructure:"tlsChecks"`
-	K8sChecks  map[string]K8sCheck  `mapstructure:"k8sChecks"`
-	K8sPings   map[string]K8sPing   `mapstructure:"k8sPings"`
+	Informer        InformerCfg               `mapstructure:"informer,omitempty"`
+	Concurrency     ConcurrencyCfg            `mapstructure:"concurrency,omitempty"`
+	History         HistoryCfg                `mapstructure:"history,omitempty"`
+	Store           StoreCfg                  `mapstructure:"store,omitempty"`
+	Metrics         MetricsCfg                `mapstructure:"metrics,omitempty"`
+	HTTPChecks      map[string]HTTPCheck      `mapstructure:"httpChecks"`
+	GRPCChecks      map[string]GRPCCheck      `mapstructure:"grpcChecks"`
+	DNSChecks       map[string]DNSCheck       `mapstructure:"dnsChecks"`
+	ConnChecks      map[string]ConnCheck      `mapstructure:"connChecks"`
+	TLSChecks       map[string]TLSCheck       `mapstructure:"tlsChecks"`
+	K8sChecks       map[string]K8sCheck       `mapstructure:"k8sChecks"`
+	K8sPings        map[string]K8sPing        `mapstructure:"k8sPings"`
+	WebSocketChecks map[string]WebSocketCheck `mapstructure:"websocketChecks"`
+	GraphQLChecks   map[string]GraphQLCheck   `mapstructure:"graphqlChecks"`
+	HeartbeatChecks map[string]HeartbeatCheck `mapstructure:"heartbeatChecks"`
+	PromQueryChecks map[string]PromQueryCheck `mapstructure:"promQueryChecks"`
+	CompositeChecks map[string]CompositeCheck `mapstructure:"compositeChecks"`
+	Silences        map[string]Silence        `mapstructure:"silences,omitempty"`
+	Groups          map[string]Group          `mapstructure:"groups,omitempty"`
+	Evaluation      EvaluationPolicy          `mapstructure:"evaluation,omitempty"`
+	Notifiers       NotifiersCfg              `mapstructure:"notifiers,omitempty"`
 }
 
 type InformerCfg struct {
@@ -27,6 +42,48 @@ type InformerCfg struct {
 	Upstreams       []Upstream      `mapstructure:"upstreams,omitempty"`
 }
 
+// ConcurrencyCfg limits how many checks can be executed at the same time.
+// Checks that are due while the limits are reached wait in a queue.
+type ConcurrencyCfg struct {
+	// MaxChecks is the maximum number of checks that can be executed at the same time, defaults to 0 (unlimited)
+	MaxChecks int `mapstructure:"maxChecks,omitempty"`
+	// MaxChecksPerHost is the maximum number of checks targeting the same host that can be executed at the same time, defaults to 0 (unlimited)
+	MaxChecksPerHost int `mapstructure:"maxChecksPerHost,omitempty"`
+}
+
+const (
+	// DefaultHistorySize is the default maximum number of results kept for each check
+	DefaultHistorySize = 10000
+	// DefaultHistoryRetention is the default time results are kept for
+	DefaultHistoryRetention = 30 * 24 * time.Hour
+	// DefaultSLOWindow is the default period over which the SLO target is evaluated
+	DefaultSLOWindow = 30 * 24 * time.Hour
+)
+
+// HistoryCfg bounds the results kept in memory for each check.
+// Results are dropped once any of the limits is reached.
+type HistoryCfg struct {
+	// Size is the maximum number of results kept for each check, defaults to 10000
+	Size int `mapstructure:"size,omitempty"`
+	// Retention is how long results are kept for, defaults to 30 days
+	Retention metav1.Duration `mapstructure:"retention,omitempty"`
+}
+
+// MetricsCfg configures the Prometheus metrics
+type MetricsCfg struct {
+	// Labels is a list of check label keys to add to the check metrics, e.g.: ["team"].
+	// Characters not allowed in Prometheus label names are replaced with underscores.
+	Labels []string `mapstructure:"labels,omitempty"`
+}
+
+// StoreCfg configures where the state of the checks is persisted, so that it survives restarts
+type StoreCfg struct {
+	// Type is the kind of store to use, currently only "bolt" is supported, defaults to "bolt"
+	Type string `mapstructure:"type,omitempty"`
+	// Path is the file where the state is persisted, nothing is persisted if not set
+	Path string `mapstructure:"path,omitempty"`
+}
+
 // Upstream represents an upstream synthetic-checker where to push checks to.
 // This is useful when combined with the insgress watcher to generate remote checks for the local cluster
 type Upstream struct {
@@ -39,10 +96,188 @@ type Upstream struct {
 type BaseCheck struct {
 	// Timeout is the timeout used for the check duration, defaults to "1s".
 	Timeout metav1.Duration `mapstructure:"timeout,omitempty"`
+	// MaxDuration is optional, when set, successful executions taking longer are reported as failed.
+	MaxDuration metav1.Duration `mapstructure:"maxDuration,omitempty"`
+	// WarnDuration is optional, when set, successful executions taking longer are reported as a warning.
+	WarnDuration metav1.Duration `mapstructure:"warnDuration,omitempty"`
 	// Interval defines how often the check should be executed, defaults to 30 seconds.
 	Interval metav1.Duration `mapstructure:"interval,omitempty"`
 	// InitialDelay defines a time to wait for before starting the check
 	InitialDelay metav1.Duration `mapstructure:"initialDelay,omitempty"`
+	// DependsOn is a list of check names, as reported in the status, this check depends on.
+	// While any of them is failing, or hasn't run yet, this check is skipped instead of being executed.
+	DependsOn []string `mapstructure:"dependsOn,omitempty"`
+	// FailureThreshold is the number of consecutive failures required for the check to be reported as failed, defaults to 1.
+	FailureThreshold int `mapstructure:"failureThreshold,omitempty"`
+	// SuccessThreshold is the number of consecutive successes required for a failed check to be reported as OK again, defaults to 1.
+	SuccessThreshold int `mapstructure:"successThreshold,omitempty"`
+	// Retries is the number of times a failing check is re-executed, within the same interval, before recording a failure.
+	Retries int `mapstructure:"retries,omitempty"`
+	// RetryDelay is how long to wait before retrying a failed execution, defaults to 1s.
+	RetryDelay metav1.Duration `mapstructure:"retryDelay,omitempty"`
+	// RetryBackoff is the factor by which the RetryDelay is multiplied after each retry, defaults to 1.
+	RetryBackoff float64 `mapstructure:"retryBackoff,omitempty"`
+	// Schedule is an optional cron expression, e.g.: "*/5 * * * *", when set it takes precedence over the Interval.
+	Schedule string `mapstructure:"schedule,omitempty"`
+	// Timezone is the IANA time zone used to evaluate the Schedule and ActiveWindows, defaults to UTC.
+	Timezone string `mapstructure:"timezone,omitempty"`
+	// Jitter is a percentage of the interval between executions used to randomly delay the executions, spreading the load.
+	// Checks using an interval are delayed once, before the first execution, cron scheduled ones are delayed on each execution.
+	Jitter int `mapstructure:"jitter,omitempty"`
+	// ActiveWindows is an optional list of time windows, when set the check is only executed within them.
+	ActiveWindows []TimeWindow `mapstructure:"activeWindows,omitempty"`
+	// Labels are arbitrary key value pairs that can be used to select checks
+	Labels map[string]string `mapstructure:"labels,omitempty"`
+	// Owner identifies who is responsible for the check, e.g.: a team name or an email address
+	Owner string `mapstructure:"owner,omitempty"`
+	// Severity indicates how important a failure of the check is, e.g.: "critical" or "warning"
+	Severity string `mapstructure:"severity,omitempty"`
+	// Critical checks make the overall status failed as soon as they fail, other checks are optional
+	Critical bool `mapstructure:"critical,omitempty"`
+	// Description explains what the check is about
+	Description string `mapstructure:"description,omitempty"`
+	// RunbookURL points to the instructions on what to do when the check fails
+	RunbookURL string `mapstructure:"runbookURL,omitempty"`
+	// SLO is an optional service level objective, used to track the check's error budget
+	SLO SLO `mapstructure:"slo,omitempty"`
+}
+
+// SLO defines a service level objective based on the percentage of successful executions of a check.
+// It's evaluated from the check's history, so the history retention should cover the SLO window.
+type SLO struct {
+	// Target is the percentage of executions that must succeed, e.g.: 99.9
+	Target float64 `mapstructure:"target,omitempty"`
+	// Window is the period over which the target is evaluated, defaults to 720h (30 days)
+	Window metav1.Duration `mapstructure:"window,omitempty"`
+}
+
+// TimeWindow represents a daily time range, optionally restricted to some days of the week
+type TimeWindow struct {
+	// Days is a list of week days, e.g.: ["mon", "tue"], defaults to every day
+	Days []string `mapstructure:"days,omitempty"`
+	// Start is the time of the day when the window starts, in the 24h "15:04" format
+	Start string `mapstructure:"start"`
+	// End is the time of the day when the window ends, in the 24h "15:04" format.
+	// If End is before Start, the window ends on the next day.
+	End string `mapstructure:"end"`
+}
+
+// SilenceMode controls how silenced checks are handled
+type SilenceMode string
+
+const (
+	// SilenceModeSilence executes the checks but marks their results as silenced
+	SilenceModeSilence SilenceMode = "silence"
+	// SilenceModeSkip doesn't execute the checks while silenced
+	SilenceModeSkip SilenceMode = "skip"
+)
+
+// Silence pauses or silences the matching checks, e.g.: during planned maintenance.
+// A check matches if it matches any of the names or types and all the labels.
+// Silenced checks are not taken into account when evaluating the overall status.
+type Silence struct {
+	// Checks is a list of check names, as configured or as reported in the status, e.g.: "example" or "example-http"
+	Checks []string `mapstructure:"checks,omitempty"`
+	// Types is a list of check types, e.g.: "http"
+	Types []string `mapstructure:"types,omitempty"`
+	// Labels selects the checks having all the given labels
+	Labels map[string]string `mapstructure:"labels,omitempty"`
+	// Mode is either "silence" or "skip", defaults to "silence"
+	Mode SilenceMode `mapstructure:"mode,omitempty"`
+	// StartsAt is when the silence starts, defaults to now
+	StartsAt time.Time `mapstructure:"startsAt,omitempty"`
+	// EndsAt is when the silence ends, defaults to never
+	EndsAt time.Time `mapstructure:"endsAt,omitempty"`
+	// Windows is an optional list of recurring time windows, when set the silence is only active within them
+	Windows []TimeWindow `mapstructure:"windows,omitempty"`
+	// Timezone is the IANA time zone used to evaluate the Windows, defaults to UTC.
+	Timezone string `mapstructure:"timezone,omitempty"`
+	// Comment describes the reason for the silence
+	Comment string `mapstructure:"comment,omitempty"`
+}
+
+// NotifiersCfg configures where to send notifications about the checks, by receiver type
+type NotifiersCfg struct {
+	Webhooks      map[string]WebhookNotifier      `mapstructure:"webhooks,omitempty"`
+	Alertmanagers map[string]AlertmanagerNotifier `mapstructure:"alertmanagers,omitempty"`
+}
+
+// BaseNotifier holds the settings common to all notifiers
+type BaseNotifier struct {
+	// Events is the list of event types to notify about, defaults to ["StatusChanged"],
+	// i.e.: when a check starts failing and when it recovers
+	Events []string `mapstructure:"events,omitempty"`
+	// Labels selects the checks having all the given labels
+	Labels map[string]string `mapstructure:"labels,omitempty"`
+	// Severities selects the checks with any of the given severities, e.g.: ["critical"]
+	Severities []string `mapstructure:"severities,omitempty"`
+	// Timeout is the timeout of each delivery attempt, defaults to 10s
+	Timeout metav1.Duration `mapstructure:"timeout,omitempty"`
+	// Retries is the number of times a failed delivery is retried, defaults to 0
+	Retries int `mapstructure:"retries,omitempty"`
+	// RetryDelay is how long to wait before retrying a failed delivery, defaults to 1s
+	RetryDelay metav1.Duration `mapstructure:"retryDelay,omitempty"`
+	// RetryBackoff is the factor by which the RetryDelay is multiplied after each retry, defaults to 2
+	RetryBackoff float64 `mapstructure:"retryBackoff,omitempty"`
+}
+
+// WebhookNotifier sends the events to an HTTP endpoint
+type WebhookNotifier struct {
+	// URL is the endpoint to send the events to
+	URL string `mapstructure:"url"`
+	// Method is the HTTP method, defaults to POST
+	Method string `mapstructure:"method,omitempty"`
+	// Headers are added to each request, e.g.: for authentication
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// Template is a Go template that renders the request body from the event, defaults to the JSON encoded event.
+	// Besides the standard functions, `json` encodes a value as JSON, e.g.: {"text": {{ .Status.Error | json }}}
+	Template string `mapstructure:"template,omitempty"`
+	// Secret is optional, when set, the request body is signed with HMAC-SHA256 and the signature is sent in the SignatureHeader
+	Secret string `mapstructure:"secret,omitempty"`
+	// SignatureHeader is the header holding the signature, as "sha256=<hex encoded signature>", defaults to X-Signature-256
+	SignatureHeader string `mapstructure:"signatureHeader,omitempty"`
+	BaseNotifier    `mapstructure:",squash"`
+}
+
+// AlertmanagerNotifier sends alerts for the failing checks to the Alertmanager v2 API
+type AlertmanagerNotifier struct {
+	// URL is the Alertmanager base URL, e.g.: http://alertmanager:9093
+	URL string `mapstructure:"url"`
+	// Headers are added to each request, e.g.: for authentication
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// ResendInterval is how often the alerts are re-sent while the checks are failing, defaults to 1m
+	ResendInterval metav1.Duration `mapstructure:"resendInterval,omitempty"`
+	BaseNotifier   `mapstructure:",squash"`
+}
+
+// Group selects a subset of the checks whose statuses are evaluated together,
+// e.g.: to back a load balancer health check or a status page component.
+// A check belongs to the group if it's listed in Checks or if it matches the Types and Labels, when set.
+type Group struct {
+	// Checks is a list of check names, as reported in the status, e.g.: "example-http"
+	Checks []string `mapstructure:"checks,omitempty"`
+	// Types is a list of check types, e.g.: "http"
+	Types []string `mapstructure:"types,omitempty"`
+	// Labels selects the checks having all the given labels
+	Labels map[string]string `mapstructure:"labels,omitempty"`
+	// Evaluation defines when the group is reported as failed or degraded
+	Evaluation EvaluationPolicy `mapstructure:"evaluation,omitempty"`
+	// FailedStatusCode is the HTTP status code returned when the group is failed, defaults to the server's failed status code
+	FailedStatusCode int `mapstructure:"failedStatusCode,omitempty"`
+	// DegradedStatusCode is the HTTP status code returned when the group is degraded, defaults to the server's degraded status code
+	DegradedStatusCode int `mapstructure:"degradedStatusCode,omitempty"`
+}
+
+// EvaluationPolicy defines when a set of checks is reported as failed or degraded.
+// Any critical check failing makes the status failed, otherwise the status is based on the percentage of failing optional checks.
+// Skipped and silenced checks are not taken into account.
+type EvaluationPolicy struct {
+	// FailedPercent is the percentage of failing optional checks from which the status is failed, defaults to 100, i.e.: all checks failing
+	FailedPercent int `mapstructure:"failedPercent,omitempty"`
+	// DegradedPercent is the percentage of failing optional checks from which the status is degraded, defaults to 0, i.e.: any check failing
+	DegradedPercent int `mapstructure:"degradedPercent,omitempty"`
+	// CriticalFailures is the number of failing critical checks from which the status is failed, defaults to 1
+	CriticalFailures int `mapstructure:"criticalFailures,omitempty"`
 }
 
 // HTTPCheck configures a check for the response from a given URL.
@@ -63,7 +298,56 @@ type HTTPCheck struct {
 	ExpectedBody string `mapstructure:"expectedBody,omitempty"`
 	// CertExpiryThreshold is the minimum amount of time that the TLS certificate should be valid for
 	CertExpiryThreshold metav1.Duration `mapstructure:"expiryThreshold,omitempty"`
-	BaseCheck
+	BaseCheck           `mapstructure:",squash"`
+}
+
+// WebSocketCheck configures a WebSocket check.
+// The check performs the upgrade handshake and can, optionally, exchange a message with the server.
+type WebSocketCheck struct {
+	// URL is the ws:// or wss:// URL to be checked.
+	URL string `mapstructure:"url"`
+	// Headers to set on the handshake request
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// Subprotocols is a list of subprotocols to request, when set the server must accept one of them
+	Subprotocols []string `mapstructure:"subprotocols,omitempty"`
+	// Message is an optional message to send once the connection is established
+	Message string `mapstructure:"message,omitempty"`
+	// Binary indicates whether the message should be sent as a binary message instead of a text message
+	Binary bool `mapstructure:"binary,omitempty"`
+	// ExpectedMessage is optional; if defined, makes the check fail if the first received message does not contain it
+	ExpectedMessage string `mapstructure:"expectedMessage,omitempty"`
+	// CertExpiryThreshold is the minimum amount of time that the TLS certificate should be valid for
+	CertExpiryThreshold metav1.Duration `mapstructure:"expiryThreshold,omitempty"`
+	// InsecureSkipVerify indicates whether the certificate should be checked when establishing the connection
+	InsecureSkipVerify bool `mapstructure:"insecureSkipVerify,omitempty"`
+	BaseCheck          `mapstructure:",squash"`
+}
+
+// GraphQLCheck configures a check that posts a query to a GraphQL endpoint.
+// The check fails if the response contains any errors or if any of the assertions on the returned data fail.
+type GraphQLCheck struct {
+	// URL is the URL of the GraphQL endpoint.
+	URL string `mapstructure:"url"`
+	// Query is the GraphQL query document to send
+	Query string `mapstructure:"query"`
+	// OperationName is optional and selects the operation to run when the query contains multiple operations
+	OperationName string `mapstructure:"operationName,omitempty"`
+	// Variables to send along with the query
+	Variables map[string]interface{} `mapstructure:"variables,omitempty"`
+	// Headers to set on the request
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// Assertions is an optional list of assertions on the returned data
+	Assertions []DataAssertion `mapstructure:"assertions,omitempty"`
+	BaseCheck  `mapstructure:",squash"`
+}
+
+// DataAssertion asserts on the value found at a given path of a response's data
+type DataAssertion struct {
+	// Path is a dot separated path to the value, relative to the response data, e.g.: `user.roles.0.name`
+	Path string `mapstructure:"path"`
+	// Value is optional; if defined, the value found at Path must match it,
+	// otherwise the value must only be present and not null
+	Value string `mapstructure:"value,omitempty"`
 }
 
 // GRPCCheck configures a gRPC health check probe
@@ -97,8 +381,8 @@ type GRPCCheck struct {
 	// GZIP indicates whether to use GZIPCompressor for requests and GZIPDecompressor for response
 	GZIP bool `mapstructure:"gzip,omitempty"`
 	// SPIFFE indicates if SPIFFE Workload API should be used to retrieve TLS credentials
-	SPIFFE bool `mapstructure:"spiffe,omitempty"`
-	BaseCheck
+	SPIFFE    bool `mapstructure:"spiffe,omitempty"`
+	BaseCheck `mapstructure:",squash"`
 }
 
 // TLSCheck configures a TLS connection check, including certificate validation
@@ -111,11 +395,14 @@ type TLSCheck struct {
 	// ExpiryThreshold is the minimum amount of time that the certificate should be valid for
 	// defaults to 168h (7 days)
 	ExpiryThreshold metav1.Duration `mapstructure:"expiryThreshold,omitempty"`
+	// WarningThreshold is the amount of time, before the certificate expires, from which the check reports a warning
+	// not set by default, it's ignored if it's not greater than the ExpiryThreshold
+	WarningThreshold metav1.Duration `mapstructure:"warningThreshold,omitempty"`
 	// InsecureSkipVerify indicates whether the certificate should be checked when establishing the connection
 	InsecureSkipVerify bool `mapstructure:"insecureSkipVerify"`
 	// SkipChainValidation limita the certificate validation to the leaf certificate
 	SkipChainValidation bool `mapstructure:"skipChainValidation,omitempty"`
-	BaseCheck
+	BaseCheck           `mapstructure:",squash"`
 }
 
 // DNSCheck configures a probe to check if a DNS record resolves
@@ -124,7 +411,9 @@ type DNSCheck struct {
 	Host string `mapstructure:"host,omitempty"`
 	// Minimum number of results the query must return, defaults to 1
 	MinRequiredResults int `mapstructure:"minRequiredResults,omitempty"`
-	BaseCheck
+	// Optional number of results below which the check reports a warning, must be greater than MinRequiredResults
+	WarningResults int `mapstructure:"warningResults,omitempty"`
+	BaseCheck      `mapstructure:",squash"`
 }
 
 // ConnCheck configures a conntivity check
@@ -138,8 +427,58 @@ type ConnCheck struct {
 	// (IPv4-only), "ip6" (IPv6-only), "unix", "unixgram" and
 	// "unixpacket".
 	// see the net.Dial doccs for details
-	Protocol string `mapstructure:"protocol,omitempty"`
-	BaseCheck
+	Protocol  string `mapstructure:"protocol,omitempty"`
+	BaseCheck `mapstructure:",squash"`
+}
+
+// HeartbeatCheck configures a passive check, also known as a dead man's switch.
+// Instead of probing a target, the check is fed by pings sent by external jobs to the heartbeats API endpoint
+// and fails if no ping arrives within `Interval` plus the `GracePeriod`.
+type HeartbeatCheck struct {
+	// GracePeriod is how long to wait past the interval before considering a ping as missed, defaults to 1m.
+	// The pings are evaluated every grace period, or every interval if shorter.
+	GracePeriod metav1.Duration `mapstructure:"gracePeriod,omitempty"`
+	// MaxRunTime is optional; if defined, makes the check fail when a job signals its start
+	// and doesn't signal its completion within this time.
+	MaxRunTime metav1.Duration `mapstructure:"maxRunTime,omitempty"`
+	// WarningExitCodes is an optional list of exit codes, e.g.: [1], that are reported as a warning instead of a failure.
+	WarningExitCodes []int `mapstructure:"warningExitCodes,omitempty"`
+	BaseCheck        `mapstructure:",squash"`
+}
+
+// PromQueryCheck configures a check that runs an instant query against a Prometheus compatible HTTP API
+// and compares the result with a threshold.
+// When the query returns a vector, every sample must satisfy the condition for the check to pass.
+type PromQueryCheck struct {
+	// URL is the base URL of the Prometheus compatible API, e.g.: `http://prometheus:9090`
+	URL string `mapstructure:"url"`
+	// Query is the PromQL expression to evaluate
+	Query string `mapstructure:"query"`
+	// Operator is used to compare the result with the threshold, as in `result <operator> threshold`.
+	// Known operators are "==", "!=", ">", ">=", "<" and "<=", defaults to "<".
+	Operator string `mapstructure:"operator,omitempty"`
+	// Threshold is the value the result is compared with
+	Threshold float64 `mapstructure:"threshold"`
+	// Headers to set on the request
+	Headers map[string]string `mapstructure:"headers,omitempty"`
+	// NoDataOK makes the check pass when the query returns no samples
+	NoDataOK  bool `mapstructure:"noDataOK,omitempty"`
+	BaseCheck `mapstructure:",squash"`
+}
+
+// CompositeCheck configures a check that combines the statuses of other checks.
+// It doesn't probe anything by itself, instead it evaluates the last known status of the referenced checks.
+type CompositeCheck struct {
+	// Checks is the list of check names to combine, as reported in the status, e.g.: `foo-http`
+	Checks []string `mapstructure:"checks,omitempty"`
+	// Mode defines how to combine the checks, known modes are "all", "any" and "atLeast", defaults to "all".
+	Mode string `mapstructure:"mode,omitempty"`
+	// AtLeast is the minimum number of checks that must pass when using the "atLeast" mode
+	AtLeast int `mapstructure:"atLeast,omitempty"`
+	// Expression is an optional boolean expression over check names, e.g.: `foo-dns && (lb1-http || lb2-http)`.
+	// Supported operators are "&&", "||", "!" and parentheses. When set, Checks and Mode are ignored.
+	Expression string `mapstructure:"expression,omitempty"`
+	BaseCheck  `mapstructure:",squash"`
 }
 
 // K8sCheck configures a check that probes the status of a Kubernetes resource.
@@ -155,7 +494,7 @@ type K8sCheck struct {
 	LabelSelector string `mapstructure:"labelSelector,omitempty"`
 	// FieldSelector comma separated list of key=value fields
 	FieldSelector string `mapstructure:"fieldSelector,omitempty"`
-	BaseCheck
+	BaseCheck     `mapstructure:",squash"`
 }
 
 // K8sPing is a conntivity check that will try to connect to all Pods matching the selector
@@ -168,6 +507,6 @@ type K8sPing struct {
 	// see the net.Dial doccs for details
 	Protocol string `mapstructure:"protocol,omitempty"`
 	// Port to ping
-	Port int `mapstructure:"port,omitempty"`
-	BaseCheck
+	Port      int `mapstructure:"port,omitempty"`
+	BaseCheck `mapstructure:",squash"`
 }
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DecodeHook returns the hooks needed to decode the configuration with mapstructure, e.g.: when using viper.
+// On top of the viper defaults, it decodes durations like "30s" into metav1.Duration fields.
+func DecodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToMetaV1DurationHookFunc(),
+	)
+}
+
+func stringToMetaV1DurationHookFunc() mapstructure.DecodeHookFuncType {
+	return func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+		if f.Kind() != reflect.String || t != reflect.TypeOf(metav1.Duration{}) {
+			return data, nil
+		}
+		d, err := time.ParseDuration(data.(string))
+		if err != nil {
+			return nil, err
+		}
+		return metav1.Duration{Duration: d}, nil
+	}
+}
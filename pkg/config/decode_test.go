@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestDecodeHook(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	err := v.ReadConfig(strings.NewReader(`
+history:
+  retention: 24h
+httpChecks:
+  example:
+    url: https://example.com
+    interval: 1m
+    timeout: 5s
+    retries: 2
+    dependsOn: ["example-dns"]
+    labels:
+      team: payments
+    activeWindows:
+      - days: ["mon"]
+        start: "08:00"
+        end: "16:00"
+silences:
+  maintenance:
+    types: ["http"]
+    endsAt: 2023-06-02T02:00:00Z
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(DecodeHook())); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := cfg.HTTPChecks["example"]
+	if check.URL != "https://example.com" {
+		t.Errorf("unexpected URL, wanted: https://example.com, got: %s", check.URL)
+	}
+	if check.Interval.Duration != time.Minute || check.Timeout.Duration != 5*time.Second || check.Retries != 2 {
+		t.Errorf("unexpected common settings, got: %+v", check.BaseCheck)
+	}
+	if len(check.DependsOn) != 1 || check.DependsOn[0] != "example-dns" {
+		t.Errorf("unexpected dependencies, got: %v", check.DependsOn)
+	}
+	if check.Labels["team"] != "payments" || len(check.ActiveWindows) != 1 {
+		t.Errorf("unexpected labels or active windows, got: %+v", check.BaseCheck)
+	}
+	if cfg.History.Retention.Duration != 24*time.Hour {
+		t.Errorf("unexpected history retention, wanted: 24h, got: %s", cfg.History.Retention.Duration)
+	}
+	if endsAt := cfg.Silences["maintenance"].EndsAt; !endsAt.Equal(time.Date(2023, 6, 2, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected silence end, got: %s", endsAt)
+	}
+}
@@ -1,10 +1,191 @@
 package config
 
 import (
+	"reflect"
+
 	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 )
 
+func (c BaseCheck) Equal(other BaseCheck) bool {
+	if c.Timeout != other.Timeout {
+		return false
+	}
+	if c.MaxDuration != other.MaxDuration {
+		return false
+	}
+	if c.WarnDuration != other.WarnDuration {
+		return false
+	}
+	if c.Interval != other.Interval {
+		return false
+	}
+	if c.InitialDelay != other.InitialDelay {
+		return false
+	}
+	if c.FailureThreshold != other.FailureThreshold {
+		return false
+	}
+	if c.SuccessThreshold != other.SuccessThreshold {
+		return false
+	}
+	if c.Retries != other.Retries {
+		return false
+	}
+	if c.RetryDelay != other.RetryDelay {
+		return false
+	}
+	if c.RetryBackoff != other.RetryBackoff {
+		return false
+	}
+	if c.Schedule != other.Schedule {
+		return false
+	}
+	if c.Timezone != other.Timezone {
+		return false
+	}
+	if c.Jitter != other.Jitter {
+		return false
+	}
+	if len(c.ActiveWindows) != len(other.ActiveWindows) {
+		return false
+	}
+	for i, w := range c.ActiveWindows {
+		if !w.Equal(other.ActiveWindows[i]) {
+			return false
+		}
+	}
+	if !maps.Equal(c.Labels, other.Labels) {
+		return false
+	}
+	if c.Owner != other.Owner {
+		return false
+	}
+	if c.Severity != other.Severity {
+		return false
+	}
+	if c.Critical != other.Critical {
+		return false
+	}
+	if c.Description != other.Description {
+		return false
+	}
+	if c.RunbookURL != other.RunbookURL {
+		return false
+	}
+	if c.SLO != other.SLO {
+		return false
+	}
+	return slices.Equal(c.DependsOn, other.DependsOn)
+}
+
+func (w TimeWindow) Equal(other TimeWindow) bool {
+	if w.Start != other.Start {
+		return false
+	}
+	if w.End != other.End {
+		return false
+	}
+	return slices.Equal(w.Days, other.Days)
+}
+
+func (c NotifiersCfg) Equal(other NotifiersCfg) bool {
+	if !maps.EqualFunc(c.Webhooks, other.Webhooks, WebhookNotifier.Equal) {
+		return false
+	}
+	return maps.EqualFunc(c.Alertmanagers, other.Alertmanagers, AlertmanagerNotifier.Equal)
+}
+
+func (c BaseNotifier) Equal(other BaseNotifier) bool {
+	if c.Timeout != other.Timeout {
+		return false
+	}
+	if c.Retries != other.Retries {
+		return false
+	}
+	if c.RetryDelay != other.RetryDelay {
+		return false
+	}
+	if c.RetryBackoff != other.RetryBackoff {
+		return false
+	}
+	if !slices.Equal(c.Events, other.Events) {
+		return false
+	}
+	if !slices.Equal(c.Severities, other.Severities) {
+		return false
+	}
+	return maps.Equal(c.Labels, other.Labels)
+}
+
+func (c WebhookNotifier) Equal(other WebhookNotifier) bool {
+	if c.URL != other.URL {
+		return false
+	}
+	if c.Method != other.Method {
+		return false
+	}
+	if c.Template != other.Template {
+		return false
+	}
+	if c.Secret != other.Secret {
+		return false
+	}
+	if c.SignatureHeader != other.SignatureHeader {
+		return false
+	}
+	if !maps.Equal(c.Headers, other.Headers) {
+		return false
+	}
+	return c.BaseNotifier.Equal(other.BaseNotifier)
+}
+
+func (c AlertmanagerNotifier) Equal(other AlertmanagerNotifier) bool {
+	if c.URL != other.URL {
+		return false
+	}
+	if c.ResendInterval != other.ResendInterval {
+		return false
+	}
+	if !maps.Equal(c.Headers, other.Headers) {
+		return false
+	}
+	return c.BaseNotifier.Equal(other.BaseNotifier)
+}
+
+func (s Silence) Equal(other Silence) bool {
+	if s.Mode != other.Mode {
+		return false
+	}
+	if !s.StartsAt.Equal(other.StartsAt) {
+		return false
+	}
+	if !s.EndsAt.Equal(other.EndsAt) {
+		return false
+	}
+	if s.Timezone != other.Timezone {
+		return false
+	}
+	if s.Comment != other.Comment {
+		return false
+	}
+	if !slices.Equal(s.Checks, other.Checks) {
+		return false
+	}
+	if !slices.Equal(s.Types, other.Types) {
+		return false
+	}
+	if len(s.Windows) != len(other.Windows) {
+		return false
+	}
+	for i, w := range s.Windows {
+		if !w.Equal(other.Windows[i]) {
+			return false
+		}
+	}
+	return maps.Equal(s.Labels, other.Labels)
+}
+
 func (c HTTPCheck) Equal(other HTTPCheck) bool {
 	if c.URL != other.URL {
 		return false
@@ -24,7 +205,59 @@ func (c HTTPCheck) Equal(other HTTPCheck) bool {
 	if c.CertExpiryThreshold != other.CertExpiryThreshold {
 		return false
 	}
-	if c.BaseCheck != other.BaseCheck {
+	if !c.BaseCheck.Equal(other.BaseCheck) {
+		return false
+	}
+
+	return maps.Equal(c.Headers, other.Headers)
+}
+
+func (c WebSocketCheck) Equal(other WebSocketCheck) bool {
+	if c.URL != other.URL {
+		return false
+	}
+	if c.Message != other.Message {
+		return false
+	}
+	if c.Binary != other.Binary {
+		return false
+	}
+	if c.ExpectedMessage != other.ExpectedMessage {
+		return false
+	}
+	if c.CertExpiryThreshold != other.CertExpiryThreshold {
+		return false
+	}
+	if c.InsecureSkipVerify != other.InsecureSkipVerify {
+		return false
+	}
+	if !c.BaseCheck.Equal(other.BaseCheck) {
+		return false
+	}
+	if !slices.Equal(c.Subprotocols, other.Subprotocols) {
+		return false
+	}
+
+	return maps.Equal(c.Headers, other.Headers)
+}
+
+func (c GraphQLCheck) Equal(other GraphQLCheck) bool {
+	if c.URL != other.URL {
+		return false
+	}
+	if c.Query != other.Query {
+		return false
+	}
+	if c.OperationName != other.OperationName {
+		return false
+	}
+	if !c.BaseCheck.Equal(other.BaseCheck) {
+		return false
+	}
+	if !slices.Equal(c.Assertions, other.Assertions) {
+		return false
+	}
+	if !reflect.DeepEqual(c.Variables, other.Variables) {
 		return false
 	}
 
@@ -74,7 +307,7 @@ func (c GRPCCheck) Equal(other GRPCCheck) bool {
 	if c.SPIFFE != other.SPIFFE {
 		return false
 	}
-	if c.BaseCheck != other.BaseCheck {
+	if !c.BaseCheck.Equal(other.BaseCheck) {
 		return false
 	}
 	if len(c.RPCHeaders) != len(other.RPCHeaders) {
@@ -95,30 +328,127 @@ func (c TLSCheck) Equal(other TLSCheck) bool {
 	if c.ExpiryThreshold != other.ExpiryThreshold {
 		return false
 	}
+	if c.WarningThreshold != other.WarningThreshold {
+		return false
+	}
 	if c.InsecureSkipVerify != other.InsecureSkipVerify {
 		return false
 	}
 	if c.SkipChainValidation != other.SkipChainValidation {
 		return false
 	}
-	if c.BaseCheck != other.BaseCheck {
+	if !c.BaseCheck.Equal(other.BaseCheck) {
 		return false
 	}
 	return slices.Equal(c.HostNames, other.HostNames)
 }
 
 func (c DNSCheck) Equal(other DNSCheck) bool {
-	return c == other
+	if c.Host != other.Host {
+		return false
+	}
+	if c.MinRequiredResults != other.MinRequiredResults {
+		return false
+	}
+	if c.WarningResults != other.WarningResults {
+		return false
+	}
+	return c.BaseCheck.Equal(other.BaseCheck)
 }
 
 func (c ConnCheck) Equal(other ConnCheck) bool {
-	return c == other
+	if c.Address != other.Address {
+		return false
+	}
+	if c.Protocol != other.Protocol {
+		return false
+	}
+	return c.BaseCheck.Equal(other.BaseCheck)
+}
+
+func (c HeartbeatCheck) Equal(other HeartbeatCheck) bool {
+	if c.GracePeriod != other.GracePeriod {
+		return false
+	}
+	if c.MaxRunTime != other.MaxRunTime {
+		return false
+	}
+	if !slices.Equal(c.WarningExitCodes, other.WarningExitCodes) {
+		return false
+	}
+	return c.BaseCheck.Equal(other.BaseCheck)
+}
+
+func (c PromQueryCheck) Equal(other PromQueryCheck) bool {
+	if c.URL != other.URL {
+		return false
+	}
+	if c.Query != other.Query {
+		return false
+	}
+	if c.Operator != other.Operator {
+		return false
+	}
+	if c.Threshold != other.Threshold {
+		return false
+	}
+	if c.NoDataOK != other.NoDataOK {
+		return false
+	}
+	if !c.BaseCheck.Equal(other.BaseCheck) {
+		return false
+	}
+
+	return maps.Equal(c.Headers, other.Headers)
+}
+
+func (c CompositeCheck) Equal(other CompositeCheck) bool {
+	if c.Mode != other.Mode {
+		return false
+	}
+	if c.AtLeast != other.AtLeast {
+		return false
+	}
+	if c.Expression != other.Expression {
+		return false
+	}
+	if !c.BaseCheck.Equal(other.BaseCheck) {
+		return false
+	}
+	return slices.Equal(c.Checks, other.Checks)
 }
 
 func (c K8sCheck) Equal(other K8sCheck) bool {
-	return c == other
+	if c.Kind != other.Kind {
+		return false
+	}
+	if c.Namespace != other.Namespace {
+		return false
+	}
+	if c.Name != other.Name {
+		return false
+	}
+	if c.LabelSelector != other.LabelSelector {
+		return false
+	}
+	if c.FieldSelector != other.FieldSelector {
+		return false
+	}
+	return c.BaseCheck.Equal(other.BaseCheck)
 }
 
 func (c K8sPing) Equal(other K8sPing) bool {
-	return c == other
+	if c.Namespace != other.Namespace {
+		return false
+	}
+	if c.LabelSelector != other.LabelSelector {
+		return false
+	}
+	if c.Protocol != other.Protocol {
+		return false
+	}
+	if c.Port != other.Port {
+		return false
+	}
+	return c.BaseCheck.Equal(other.BaseCheck)
 }
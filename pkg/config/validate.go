@@ -0,0 +1,284 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// TimeOfDayFormat is the format used for the start and end of time windows
+const TimeOfDayFormat = "15:04"
+
+var weekDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWeekday parses the name of a day of the week, only the first 3 letters are taken into account
+func ParseWeekday(day string) (time.Weekday, error) {
+	day = strings.ToLower(day)
+	if len(day) >= 3 {
+		if d, ok := weekDays[day[:3]]; ok {
+			return d, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid day of the week %q", day)
+}
+
+// Location returns the time zone to use for the check's schedule and active windows
+func (c BaseCheck) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// CronSchedule parses the check's cron schedule, it returns nil if no schedule is set
+func (c BaseCheck) CronSchedule() (cron.Schedule, error) {
+	if c.Schedule == "" {
+		return nil, nil
+	}
+	return cron.ParseStandard(c.Schedule)
+}
+
+// Validate checks if the common check settings are valid
+func (c BaseCheck) Validate() error {
+	if _, err := c.Location(); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	if _, err := c.CronSchedule(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	if c.MaxDuration.Duration > 0 && c.WarnDuration.Duration >= c.MaxDuration.Duration {
+		return fmt.Errorf("warnDuration must be shorter than maxDuration")
+	}
+	if c.SLO.Target < 0 || c.SLO.Target >= 100 {
+		return fmt.Errorf("the SLO target must be a percentage between 0 and 100, excluding 100")
+	}
+	if c.SLO.Window.Duration < 0 {
+		return fmt.Errorf("the SLO window must not be negative")
+	}
+	if c.Jitter < 0 || c.Jitter > 100 {
+		return fmt.Errorf("jitter must be a percentage between 0 and 100")
+	}
+	for _, w := range c.ActiveWindows {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid active window: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if the time window is valid
+func (w TimeWindow) Validate() error {
+	if _, err := time.Parse(TimeOfDayFormat, w.Start); err != nil {
+		return fmt.Errorf("invalid start time %q", w.Start)
+	}
+	if _, err := time.Parse(TimeOfDayFormat, w.End); err != nil {
+		return fmt.Errorf("invalid end time %q", w.End)
+	}
+	for _, d := range w.Days {
+		if _, err := ParseWeekday(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains checks if the given time is within the window, using the time's location
+func (w TimeWindow) Contains(t time.Time) bool {
+	start, err := time.Parse(TimeOfDayFormat, w.Start)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse(TimeOfDayFormat, w.End)
+	if err != nil {
+		return false
+	}
+	minutes := func(t time.Time) int { return t.Hour()*60 + t.Minute() }
+	now, from, to := minutes(t), minutes(start), minutes(end)
+
+	day := t.Weekday()
+	if from > to && now < to {
+		// the window started on the previous day
+		day = (day + 6) % 7
+	}
+	if len(w.Days) > 0 {
+		found := false
+		for _, d := range w.Days {
+			if wd, err := ParseWeekday(d); err == nil && wd == day {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if from <= to {
+		return now >= from && now < to
+	}
+	return now >= from || now < to
+}
+
+// Validate checks if the silence is valid
+func (s Silence) Validate() error {
+	if len(s.Checks) == 0 && len(s.Types) == 0 && len(s.Labels) == 0 {
+		return fmt.Errorf("at least one of checks, types or labels must be set")
+	}
+	switch s.Mode {
+	case "", SilenceModeSilence, SilenceModeSkip:
+	default:
+		return fmt.Errorf("unknown silence mode %q, must be one of %s or %s", s.Mode, SilenceModeSilence, SilenceModeSkip)
+	}
+	if !s.StartsAt.IsZero() && !s.EndsAt.IsZero() && !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("endsAt must be after startsAt")
+	}
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	for _, w := range s.Windows {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("invalid window: %w", err)
+		}
+	}
+	return nil
+}
+
+// Location returns the time zone to use for the silence's windows
+func (s Silence) Location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Active checks if the silence is in effect at the given time
+func (s Silence) Active(t time.Time) bool {
+	if !s.StartsAt.IsZero() && t.Before(s.StartsAt) {
+		return false
+	}
+	if !s.EndsAt.IsZero() && !t.Before(s.EndsAt) {
+		return false
+	}
+	if len(s.Windows) == 0 {
+		return true
+	}
+	if loc, err := s.Location(); err == nil {
+		t = t.In(loc)
+	}
+	for _, w := range s.Windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches checks if the silence applies to a check with the given name, type and labels
+func (s Silence) Matches(name, checkType string, labels map[string]string) bool {
+	if len(s.Checks) > 0 || len(s.Types) > 0 {
+		matched := false
+		for _, n := range s.Checks {
+			if n == name || n == name+"-"+checkType {
+				matched = true
+				break
+			}
+		}
+		for _, t := range s.Types {
+			if t == checkType {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for k, v := range s.Labels {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks if the group is valid
+func (g Group) Validate() error {
+	if len(g.Checks) == 0 && len(g.Types) == 0 && len(g.Labels) == 0 {
+		return fmt.Errorf("at least one of checks, types or labels must be set")
+	}
+	return g.Evaluation.Validate()
+}
+
+// Validate checks if the evaluation policy is valid
+func (p EvaluationPolicy) Validate() error {
+	if p.FailedPercent < 0 || p.FailedPercent > 100 {
+		return fmt.Errorf("failedPercent must be a percentage between 0 and 100")
+	}
+	if p.DegradedPercent < 0 || p.DegradedPercent > 100 {
+		return fmt.Errorf("degradedPercent must be a percentage between 0 and 100")
+	}
+	if p.CriticalFailures < 0 {
+		return fmt.Errorf("criticalFailures must not be negative")
+	}
+	return nil
+}
+
+// Validate checks if the settings common to all notifiers are valid
+func (c BaseNotifier) Validate() error {
+	if c.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("retryBackoff must not be negative")
+	}
+	return nil
+}
+
+// invalidLabelChars matches the characters that are not allowed in Prometheus label names
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// reservedMetricLabels are the label names already used by the check metrics
+var reservedMetricLabels = []string{"name", "status", "window", "le"}
+
+// MetricLabelName converts a check label key into a valid Prometheus label name, e.g.: "app.kubernetes.io/name" => "app_kubernetes_io_name"
+func MetricLabelName(key string) string {
+	return invalidLabelChars.ReplaceAllString(key, "_")
+}
+
+// Validate checks if the metric labels can be added to the check metrics,
+// they must not clash with the built-in labels or with each other once converted into label names
+func (c MetricsCfg) Validate() error {
+	keys := make(map[string]string, len(c.Labels))
+	for _, l := range c.Labels {
+		name := MetricLabelName(l)
+		switch {
+		case name == "":
+			return fmt.Errorf("metric labels must not be empty")
+		case name[0] >= '0' && name[0] <= '9':
+			return fmt.Errorf("metric label %q must not start with a digit", l)
+		case strings.HasPrefix(name, "__"):
+			return fmt.Errorf("metric label %q must not start with \"__\"", l)
+		}
+		for _, r := range reservedMetricLabels {
+			if name == r {
+				return fmt.Errorf("metric label %q clashes with the built-in %q label", l, r)
+			}
+		}
+		if other, ok := keys[name]; ok {
+			return fmt.Errorf("metric labels %q and %q both map to %q", other, l, name)
+		}
+		keys[name] = l
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var (
+	_ Resender = &alertmanager{}
+	_ Seeder   = &alertmanager{}
+)
+
+// defaultAlertmanagerEvents are the events the alertmanager notifiers get by default,
+// the deleted checks need to have their alerts resolved
+var defaultAlertmanagerEvents = []string{string(api.EventStatusChanged), string(api.EventCheckDeleted)}
+
+// alert is an Alertmanager v2 API alert
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	firing      bool
+}
+
+type alertmanager struct {
+	config *config.AlertmanagerNotifier
+	url    string
+	client *http.Client
+	// alerts holds the firing alerts and the resolved ones that failed to be sent, by check name
+	alerts map[string]alert
+	sync.Mutex
+}
+
+// NewAlertmanager returns a sender that posts alerts for the failing checks to the Alertmanager v2 API,
+// the alerts are resolved when the checks recover or are deleted
+func NewAlertmanager(config config.AlertmanagerNotifier) (Sender, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("url must not be empty")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if config.ResendInterval.Duration < 0 {
+		return nil, fmt.Errorf("resendInterval must not be negative")
+	}
+	if config.ResendInterval.Duration == 0 {
+		config.ResendInterval.Duration = time.Minute
+	}
+	return &alertmanager{
+		config: &config,
+		url:    strings.TrimSuffix(config.URL, "/") + "/api/v2/alerts",
+		client: &http.Client{},
+		alerts: make(map[string]alert),
+	}, nil
+}
+
+// newAlert builds the alert for the given check status
+func newAlert(name string, status api.Status) alert {
+	a := alert{
+		Labels:      make(map[string]string, len(status.Labels)+3),
+		Annotations: make(map[string]string),
+		StartsAt:    status.TimeOfFirstFailure,
+	}
+	for k, v := range status.Labels {
+		a.Labels[k] = v
+	}
+	a.Labels["alertname"] = name
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		a.Labels["type"] = name[i+1:]
+	}
+	if status.Severity != "" {
+		a.Labels["severity"] = status.Severity
+	}
+	if status.Error != "" {
+		a.Annotations["error"] = status.Error
+	}
+	if status.Description != "" {
+		a.Annotations["description"] = status.Description
+	}
+	if status.RunbookURL != "" {
+		a.Annotations["runbook_url"] = status.RunbookURL
+	}
+	return a
+}
+
+// endsAt returns when a firing alert should be considered resolved by Alertmanager if it's not re-sent,
+// e.g.: because the checker stopped
+func (a *alertmanager) endsAt(now time.Time) time.Time {
+	return now.Add(4 * a.config.ResendInterval.Duration)
+}
+
+// Send posts the alert for the check in the event, firing if the check is failing and resolved otherwise
+func (a *alertmanager) Send(ctx context.Context, event api.Event) error {
+	a.Lock()
+	var al alert
+	if event.Status != nil {
+		al = newAlert(event.Name, *event.Status)
+		al.firing = !event.Status.OK
+	} else {
+		// the check was deleted, resolve its alert if it's firing
+		var ok bool
+		if al, ok = a.alerts[event.Name]; !ok {
+			a.Unlock()
+			return nil
+		}
+		al.firing = false
+	}
+	if al.StartsAt.IsZero() {
+		al.StartsAt = event.Timestamp
+	}
+	if al.firing {
+		al.EndsAt = a.endsAt(time.Now())
+	} else {
+		al.EndsAt = event.Timestamp
+	}
+	a.alerts[event.Name] = al
+	a.Unlock()
+
+	if err := a.post(ctx, []alert{al}); err != nil {
+		return err
+	}
+	if !al.firing {
+		a.Lock()
+		if current, ok := a.alerts[event.Name]; ok && !current.firing {
+			delete(a.alerts, event.Name)
+		}
+		a.Unlock()
+	}
+	return nil
+}
+
+// Seed tracks the alert of the failing check in the event, so that it's re-sent and resolved when the check recovers,
+// the alerts already being tracked are kept
+func (a *alertmanager) Seed(event api.Event) {
+	if event.Status == nil || event.Status.OK {
+		return
+	}
+	a.Lock()
+	defer a.Unlock()
+	if _, ok := a.alerts[event.Name]; ok {
+		return
+	}
+	al := newAlert(event.Name, *event.Status)
+	al.firing = true
+	if al.StartsAt.IsZero() {
+		al.StartsAt = event.Timestamp
+	}
+	al.EndsAt = a.endsAt(time.Now())
+	a.alerts[event.Name] = al
+}
+
+// Resend posts the firing alerts again, so that Alertmanager doesn't resolve them,
+// along with the resolved alerts that failed to be sent
+func (a *alertmanager) Resend(ctx context.Context) error {
+	a.Lock()
+	alerts := make([]alert, 0, len(a.alerts))
+	var resolved []string
+	endsAt := a.endsAt(time.Now())
+	for name, al := range a.alerts {
+		if al.firing {
+			al.EndsAt = endsAt
+			a.alerts[name] = al
+		} else {
+			resolved = append(resolved, name)
+		}
+		alerts = append(alerts, al)
+	}
+	a.Unlock()
+	if len(alerts) == 0 {
+		return nil
+	}
+	if err := a.post(ctx, alerts); err != nil {
+		return err
+	}
+	a.Lock()
+	for _, name := range resolved {
+		if current, ok := a.alerts[name]; ok && !current.firing {
+			delete(a.alerts, name)
+		}
+	}
+	a.Unlock()
+	return nil
+}
+
+// ResendInterval returns how often the firing alerts are re-sent
+func (a *alertmanager) ResendInterval() time.Duration {
+	return a.config.ResendInterval.Duration
+}
+
+// post sends the given alerts to Alertmanager
+func (a *alertmanager) post(ctx context.Context, alerts []alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range a.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
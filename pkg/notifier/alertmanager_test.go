@@ -0,0 +1,317 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// alertmanagerStandIn records the alerts posted to the Alertmanager v2 API
+type alertmanagerStandIn struct {
+	*httptest.Server
+	status int
+	posts  [][]alert
+	sync.Mutex
+}
+
+func newAlertmanagerStandIn(t *testing.T) *alertmanagerStandIn {
+	am := &alertmanagerStandIn{status: http.StatusOK}
+	am.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/alerts" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var alerts []alert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		am.Lock()
+		defer am.Unlock()
+		if am.status == http.StatusOK {
+			am.posts = append(am.posts, alerts)
+		}
+		w.WriteHeader(am.status)
+	}))
+	return am
+}
+
+func (am *alertmanagerStandIn) setStatus(status int) {
+	am.Lock()
+	defer am.Unlock()
+	am.status = status
+}
+
+func (am *alertmanagerStandIn) received() [][]alert {
+	am.Lock()
+	defer am.Unlock()
+	return am.posts
+}
+
+func TestAlertmanager(t *testing.T) {
+	firstFailure := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	recovery := firstFailure.Add(5 * time.Minute)
+	failing := &api.Status{
+		Error:              `unexpected status "503"`,
+		TimeOfFirstFailure: firstFailure,
+		Severity:           "critical",
+		RunbookURL:         "https://runbooks.example.com/payments",
+		Labels:             map[string]string{"team": "payments"},
+	}
+	recovered := &api.Status{
+		OK:                 true,
+		TimeOfFirstFailure: firstFailure,
+		Severity:           "critical",
+		RunbookURL:         "https://runbooks.example.com/payments",
+		Labels:             map[string]string{"team": "payments"},
+	}
+
+	am := newAlertmanagerStandIn(t)
+	defer am.Close()
+	sender, err := NewAlertmanager(config.AlertmanagerNotifier{URL: am.URL + "/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a := sender.(*alertmanager)
+
+	type expected struct {
+		posts    int
+		firing   bool
+		endsAt   time.Time
+		notFound bool
+	}
+	steps := []struct {
+		name     string
+		event    api.Event
+		resend   bool
+		status   int
+		expected expected
+	}{
+		{
+			name:     "firing",
+			event:    api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: firstFailure, Status: failing},
+			expected: expected{posts: 1, firing: true},
+		},
+		{
+			name:     "re-sent while failing",
+			resend:   true,
+			expected: expected{posts: 2, firing: true},
+		},
+		{
+			name:     "resolved",
+			event:    api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: recovery, Status: recovered},
+			expected: expected{posts: 3, endsAt: recovery, notFound: true},
+		},
+		{
+			name:     "nothing to re-send",
+			resend:   true,
+			expected: expected{posts: 3, notFound: true},
+		},
+		{
+			name:     "firing again",
+			event:    api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: firstFailure, Status: failing},
+			expected: expected{posts: 4, firing: true},
+		},
+		{
+			name:     "failed to resolve on deletion",
+			event:    api.Event{Type: api.EventCheckDeleted, Name: "payments-http", Timestamp: recovery},
+			status:   http.StatusServiceUnavailable,
+			expected: expected{posts: 4},
+		},
+		{
+			name:     "resolution re-sent",
+			resend:   true,
+			expected: expected{posts: 5, endsAt: recovery, notFound: true},
+		},
+		{
+			name:     "deleted without alert",
+			event:    api.Event{Type: api.EventCheckDeleted, Name: "payments-http", Timestamp: recovery},
+			expected: expected{posts: 5, notFound: true},
+		},
+	}
+
+	sent := 0
+	for _, step := range steps {
+		am.setStatus(http.StatusOK)
+		if step.status != 0 {
+			am.setStatus(step.status)
+		}
+		if step.resend {
+			err = a.Resend(context.TODO())
+		} else {
+			err = a.Send(context.TODO(), step.event)
+		}
+		if (err != nil) != (step.status != 0) {
+			t.Errorf("%s: unexpected error: %v", step.name, err)
+		}
+
+		posts := am.received()
+		if len(posts) != step.expected.posts {
+			t.Fatalf("%s: unexpected number of posts, wanted: %d, got: %d", step.name, step.expected.posts, len(posts))
+		}
+		if _, ok := a.alerts["payments-http"]; ok == step.expected.notFound {
+			t.Errorf("%s: unexpected alert state, wanted tracked: %t, got: %t", step.name, !step.expected.notFound, ok)
+		}
+		if len(posts) == sent {
+			continue
+		}
+		sent = len(posts)
+
+		last := posts[len(posts)-1]
+		if len(last) != 1 {
+			t.Fatalf("%s: unexpected number of alerts, wanted: 1, got: %d", step.name, len(last))
+		}
+		got := last[0]
+		for k, v := range map[string]string{"alertname": "payments-http", "type": "http", "team": "payments", "severity": "critical"} {
+			if got.Labels[k] != v {
+				t.Errorf("%s: unexpected %s label, wanted: %s, got: %s", step.name, k, v, got.Labels[k])
+			}
+		}
+		if got.Annotations["runbook_url"] != failing.RunbookURL {
+			t.Errorf("%s: unexpected runbook annotation: %s", step.name, got.Annotations["runbook_url"])
+		}
+		if !got.StartsAt.Equal(firstFailure) {
+			t.Errorf("%s: unexpected startsAt, wanted: %s, got: %s", step.name, firstFailure, got.StartsAt)
+		}
+		if step.expected.firing {
+			if got.Annotations["error"] != failing.Error {
+				t.Errorf("%s: unexpected error annotation: %s", step.name, got.Annotations["error"])
+			}
+			if !got.EndsAt.After(time.Now()) {
+				t.Errorf("%s: expected the alert to be firing, endsAt: %s", step.name, got.EndsAt)
+			}
+		} else if !got.EndsAt.Equal(step.expected.endsAt) {
+			t.Errorf("%s: unexpected endsAt, wanted: %s, got: %s", step.name, step.expected.endsAt, got.EndsAt)
+		}
+	}
+}
+
+func TestAlertmanagerSilencedRecovery(t *testing.T) {
+	am := newAlertmanagerStandIn(t)
+	defer am.Close()
+	sender, err := NewAlertmanager(config.AlertmanagerNotifier{URL: am.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	webhook := &fakeSender{}
+	n := &Notifier{}
+	if err := n.addReceiver("alertmanager", config.BaseNotifier{Events: defaultAlertmanagerEvents}, sender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := n.addReceiver("webhook", config.BaseNotifier{}, webhook); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	source := &fakeSource{}
+	n.Start(source)
+	defer n.Stop()
+
+	recovery := time.Now()
+	source.publish(api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: recovery.Add(-time.Minute), Status: &api.Status{}})
+	source.publish(api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: recovery, Status: &api.Status{OK: true, Silenced: true}})
+
+	if len(webhook.events) != 1 {
+		t.Errorf("unexpected webhook notifications, wanted: 1, got: %d", len(webhook.events))
+	}
+	posts := am.received()
+	if len(posts) != 2 {
+		t.Fatalf("unexpected number of posts, wanted: 2, got: %d", len(posts))
+	}
+	if got := posts[1][0]; !got.EndsAt.Equal(recovery) {
+		t.Errorf("expected the alert to be resolved while silenced, endsAt: %s", got.EndsAt)
+	}
+	if _, ok := sender.(*alertmanager).alerts["payments-http"]; ok {
+		t.Errorf("expected the resolved alert not to be tracked")
+	}
+}
+
+func TestAlertmanagerSeed(t *testing.T) {
+	am := newAlertmanagerStandIn(t)
+	defer am.Close()
+	n, err := New(config.NotifiersCfg{
+		Alertmanagers: map[string]config.AlertmanagerNotifier{
+			"default": {
+				URL:            am.URL,
+				ResendInterval: metav1.Duration{Duration: 10 * time.Millisecond},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	firstFailure := time.Now().Add(-time.Hour).Truncate(time.Second)
+	source := &fakeSource{statuses: api.Statuses{
+		"payments-http": {Timestamp: time.Now(), TimeOfFirstFailure: firstFailure},
+		"healthy-http":  {OK: true, Timestamp: time.Now()},
+		"silenced-http": {Timestamp: time.Now(), TimeOfFirstFailure: firstFailure, Silenced: true},
+	}}
+	// the statuses of the checks that were failing before a restart or a reload
+	n.Start(source)
+	defer n.Stop()
+
+	deadline := time.Now().Add(time.Second)
+	for len(am.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	posts := am.received()
+	if len(posts) == 0 {
+		t.Fatalf("expected the seeded alerts to be re-sent")
+	}
+	if len(posts[0]) != 1 || posts[0][0].Labels["alertname"] != "payments-http" {
+		t.Fatalf("unexpected alerts, wanted only the one for payments-http, got: %+v", posts[0])
+	}
+	if !posts[0][0].StartsAt.Equal(firstFailure) || !posts[0][0].EndsAt.After(time.Now()) {
+		t.Errorf("expected a firing alert since %s, got: %+v", firstFailure, posts[0][0])
+	}
+
+	// the seeded alert is resolved when the check recovers
+	recovered := time.Now().Truncate(time.Second)
+	source.publish(api.Event{Type: api.EventStatusChanged, Name: "payments-http", Timestamp: recovered, Status: &api.Status{OK: true}})
+	resolved := false
+	for _, post := range am.received() {
+		for _, al := range post {
+			resolved = resolved || al.EndsAt.Equal(recovered)
+		}
+	}
+	if !resolved {
+		t.Errorf("expected the alert to be resolved at %s", recovered)
+	}
+}
+
+func TestAlertmanagerResend(t *testing.T) {
+	am := newAlertmanagerStandIn(t)
+	defer am.Close()
+	n, err := New(config.NotifiersCfg{
+		Alertmanagers: map[string]config.AlertmanagerNotifier{
+			"default": {
+				URL:            am.URL,
+				ResendInterval: metav1.Duration{Duration: 10 * time.Millisecond},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	source := &fakeSource{}
+	n.Start(source)
+	defer n.Stop()
+	source.publish(api.Event{Type: api.EventStatusChanged, Name: "example-http", Timestamp: time.Now(), Status: &api.Status{}})
+
+	deadline := time.Now().Add(time.Second)
+	for len(am.received()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if posts := len(am.received()); posts < 3 {
+		t.Errorf("expected the firing alert to be re-sent, got %d posts", posts)
+	}
+
+	if _, err := NewAlertmanager(config.AlertmanagerNotifier{}); err == nil {
+		t.Errorf("expected an error for an alertmanager without url")
+	}
+}
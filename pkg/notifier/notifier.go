@@ -0,0 +1,247 @@
+// Package notifier sends notifications about the checks to external receivers, e.g.: webhooks
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/exp/slices"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// Sender delivers an event to a receiver
+type Sender interface {
+	// Send delivers the given event, it's called again with the same event when retrying a failed delivery
+	Send(ctx context.Context, event api.Event) error
+}
+
+// Resender is implemented by the senders that keep some state that needs to be re-sent periodically, e.g.: the firing alerts
+type Resender interface {
+	Sender
+	// Resend re-sends the current state
+	Resend(ctx context.Context) error
+	// ResendInterval is how often Resend should be called
+	ResendInterval() time.Duration
+}
+
+// Seeder is implemented by the senders that keep some state built from the events, e.g.: the firing alerts,
+// so that it can be restored from the current status of the checks, after a restart or a reload
+type Seeder interface {
+	Sender
+	// Seed restores the state from the given event without sending it
+	Seed(event api.Event)
+}
+
+// Source provides the events to notify about, e.g.: a checker.Runner
+type Source interface {
+	// Subscribe registers a callback that receives the events, in order
+	Subscribe(callback func(api.Event)) (unsubscribe func())
+	// GetStatus returns the current status of all the checks
+	GetStatus() api.Statuses
+}
+
+// receiver delivers the events matching its filters to a sender
+type receiver struct {
+	name   string
+	cfg    config.BaseNotifier
+	sender Sender
+}
+
+// Notifier delivers the events to all the configured receivers,
+// each receiver gets its own subscription so that a slow receiver doesn't delay the others
+type Notifier struct {
+	receivers   []*receiver
+	cancel      context.CancelFunc
+	unsubscribe []func()
+	log         zerolog.Logger
+	sync.Mutex
+}
+
+// New creates a notifier from the given configuration
+func New(cfg config.NotifiersCfg) (*Notifier, error) {
+	n := &Notifier{
+		log: zerolog.New(os.Stderr).With().Timestamp().Str("name", "notifier").Logger().Level(zerolog.InfoLevel),
+	}
+	for name, c := range cfg.Webhooks {
+		sender, err := NewWebhook(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook %s: %w", name, err)
+		}
+		if err := n.addReceiver("webhook/"+name, c.BaseNotifier, sender); err != nil {
+			return nil, err
+		}
+	}
+	for name, c := range cfg.Alertmanagers {
+		sender, err := NewAlertmanager(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alertmanager %s: %w", name, err)
+		}
+		if len(c.Events) == 0 {
+			c.Events = defaultAlertmanagerEvents
+		}
+		if err := n.addReceiver("alertmanager/"+name, c.BaseNotifier, sender); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(n.receivers, func(i, j int) bool { return n.receivers[i].name < n.receivers[j].name })
+	return n, nil
+}
+
+// addReceiver validates the common settings and adds a receiver using the given sender
+func (n *Notifier) addReceiver(name string, cfg config.BaseNotifier, sender Sender) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid notifier %s: %w", name, err)
+	}
+	if len(cfg.Events) == 0 {
+		cfg.Events = []string{string(api.EventStatusChanged)}
+	}
+	if cfg.Timeout.Duration == 0 {
+		cfg.Timeout.Duration = 10 * time.Second
+	}
+	if cfg.RetryDelay.Duration == 0 {
+		cfg.RetryDelay.Duration = time.Second
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = 2
+	}
+	n.receivers = append(n.receivers, &receiver{name: name, cfg: cfg, sender: sender})
+	return nil
+}
+
+// Start subscribes all the receivers to the events from the given source, restores their state from its current statuses
+// and starts re-sending the state of the receivers that need it
+func (n *Notifier) Start(source Source) {
+	n.Seed(source.GetStatus())
+	n.Lock()
+	defer n.Unlock()
+	var ctx context.Context
+	ctx, n.cancel = context.WithCancel(context.Background())
+	for _, r := range n.receivers {
+		r := r
+		n.unsubscribe = append(n.unsubscribe, source.Subscribe(func(event api.Event) {
+			if r.matches(event) {
+				n.deliver(ctx, r, event)
+			}
+		}))
+		if resender, ok := r.sender.(Resender); ok {
+			go n.resend(ctx, r, resender)
+		}
+	}
+}
+
+// Seed restores the state of the receivers that keep one from the given statuses,
+// as if a status change had been notified for each of the checks matching their filters
+func (n *Notifier) Seed(statuses api.Statuses) {
+	n.Lock()
+	defer n.Unlock()
+	for _, r := range n.receivers {
+		seeder, ok := r.sender.(Seeder)
+		if !ok {
+			continue
+		}
+		for name, status := range statuses {
+			status := status
+			event := api.Event{
+				Type:      api.EventStatusChanged,
+				Name:      name,
+				Timestamp: status.Timestamp,
+				Status:    &status,
+			}
+			if r.matches(event) {
+				seeder.Seed(event)
+			}
+		}
+	}
+}
+
+// resend periodically re-sends the state of the given receiver until the context is cancelled
+func (n *Notifier) resend(ctx context.Context, r *receiver, resender Resender) {
+	ticker := time.NewTicker(resender.ResendInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			resendCtx, cancel := context.WithTimeout(ctx, r.cfg.Timeout.Duration)
+			if err := resender.Resend(resendCtx); err != nil && ctx.Err() == nil {
+				n.log.Err(err).Str("receiver", r.name).Msg("failed to re-send notifications")
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop removes the subscriptions and cancels the pending deliveries and re-sends
+func (n *Notifier) Stop() {
+	n.Lock()
+	defer n.Unlock()
+	for _, unsubscribe := range n.unsubscribe {
+		unsubscribe()
+	}
+	n.unsubscribe = nil
+	if n.cancel != nil {
+		n.cancel()
+	}
+}
+
+// matches checks if the given event passes the receiver's filters, the label and severity filters only match events with a status,
+// the events of silenced checks are ignored, except for the recoveries sent to the receivers that keep state,
+// so that e.g.: a firing alert doesn't stay open until the silence ends
+func (r *receiver) matches(event api.Event) bool {
+	if !slices.Contains(r.cfg.Events, string(event.Type)) {
+		return false
+	}
+	if event.Status != nil && event.Status.Silenced {
+		if _, stateful := r.sender.(Seeder); !stateful || !event.Status.OK {
+			return false
+		}
+	}
+	if len(r.cfg.Labels) == 0 && len(r.cfg.Severities) == 0 {
+		return true
+	}
+	if event.Status == nil {
+		return false
+	}
+	if len(r.cfg.Severities) > 0 && !slices.Contains(r.cfg.Severities, event.Status.Severity) {
+		return false
+	}
+	for k, v := range r.cfg.Labels {
+		if l, ok := event.Status.Labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	return true
+}
+
+// deliver sends the event to the given receiver, retrying failed deliveries according to its configuration
+func (n *Notifier) deliver(ctx context.Context, r *receiver, event api.Event) {
+	delay := r.cfg.RetryDelay.Duration
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, r.cfg.Timeout.Duration)
+		err := r.sender.Send(attemptCtx, event)
+		cancel()
+		if err == nil {
+			n.log.Debug().Str("receiver", r.name).Str("name", event.Name).Str("event", string(event.Type)).Msg("notification sent")
+			return
+		}
+		if attempt > r.cfg.Retries || ctx.Err() != nil {
+			n.log.Err(err).Str("receiver", r.name).Str("name", event.Name).Str("event", string(event.Type)).Int("attempts", attempt).Msg("failed to send notification")
+			return
+		}
+		n.log.Err(err).Str("receiver", r.name).Str("name", event.Name).Int("attempt", attempt).Dur("retryIn", delay).Msg("failed to send notification, retrying")
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		delay = time.Duration(float64(delay) * r.cfg.RetryBackoff)
+	}
+}
@@ -0,0 +1,147 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// fakeSource delivers the published events synchronously
+type fakeSource struct {
+	callbacks []func(api.Event)
+	statuses  api.Statuses
+}
+
+func (s *fakeSource) GetStatus() api.Statuses {
+	return s.statuses
+}
+
+func (s *fakeSource) Subscribe(callback func(api.Event)) func() {
+	s.callbacks = append(s.callbacks, callback)
+	return func() {}
+}
+
+func (s *fakeSource) publish(event api.Event) {
+	for _, callback := range s.callbacks {
+		callback(event)
+	}
+}
+
+// fakeSender records the events and fails the first given number of deliveries
+type fakeSender struct {
+	failures int
+	attempts int
+	events   []api.Event
+	sync.Mutex
+}
+
+func (s *fakeSender) Send(ctx context.Context, event api.Event) error {
+	s.Lock()
+	defer s.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("failed")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func TestNotifier(t *testing.T) {
+	payments := &api.Status{Severity: "critical", Labels: map[string]string{"team": "payments"}}
+	edge := &api.Status{Severity: "warning", Labels: map[string]string{"team": "edge"}}
+	events := []api.Event{
+		{Type: api.EventCheckAdded, Name: "payments-http"},
+		{Type: api.EventStatusChanged, Name: "payments-http", Status: payments},
+		{Type: api.EventFirstFailure, Name: "payments-http", Status: payments},
+		{Type: api.EventStatusChanged, Name: "edge-http", Status: edge},
+		{Type: api.EventRecovered, Name: "edge-http", Status: edge},
+		{Type: api.EventStatusChanged, Name: "silenced-http", Status: &api.Status{Severity: "critical", Silenced: true}},
+	}
+	retry := config.BaseNotifier{RetryDelay: metav1.Duration{Duration: time.Millisecond}}
+	tests := []struct {
+		name     string
+		cfg      config.BaseNotifier
+		failures int
+		expected []string
+	}{
+		{
+			name:     "default events",
+			expected: []string{"payments-http", "edge-http"},
+		},
+		{
+			name:     "by event type",
+			cfg:      config.BaseNotifier{Events: []string{"CheckAdded", "Recovered"}},
+			expected: []string{"payments-http", "edge-http"},
+		},
+		{
+			name:     "by labels",
+			cfg:      config.BaseNotifier{Events: []string{"CheckAdded", "StatusChanged"}, Labels: map[string]string{"team": "payments"}},
+			expected: []string{"payments-http"},
+		},
+		{
+			name:     "by severity",
+			cfg:      config.BaseNotifier{Severities: []string{"warning"}},
+			expected: []string{"edge-http"},
+		},
+		{
+			name: "retried",
+			cfg: config.BaseNotifier{
+				Retries:    2,
+				RetryDelay: retry.RetryDelay,
+				Severities: []string{"critical"},
+			},
+			failures: 2,
+			expected: []string{"payments-http"},
+		},
+		{
+			name: "retries exhausted",
+			cfg: config.BaseNotifier{
+				Retries:    1,
+				RetryDelay: retry.RetryDelay,
+				Severities: []string{"critical"},
+			},
+			failures: 2,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := &fakeSender{failures: tt.failures}
+			n := &Notifier{}
+			if err := n.addReceiver("test", tt.cfg, sender); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			source := &fakeSource{}
+			n.Start(source)
+			defer n.Stop()
+			for _, event := range events {
+				source.publish(event)
+			}
+
+			var names []string
+			for _, event := range sender.events {
+				names = append(names, event.Name)
+			}
+			if len(names) != len(tt.expected) {
+				t.Fatalf("unexpected notifications, wanted: %v, got: %v", tt.expected, names)
+			}
+			for i := range names {
+				if names[i] != tt.expected[i] {
+					t.Errorf("unexpected notifications, wanted: %v, got: %v", tt.expected, names)
+				}
+			}
+		})
+	}
+
+	if _, err := New(config.NotifiersCfg{Webhooks: map[string]config.WebhookNotifier{"invalid": {}}}); err == nil {
+		t.Errorf("expected an error for a webhook without url")
+	}
+}
@@ -0,0 +1,106 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var _ Sender = &webhook{}
+
+// templateFuncs are the functions available in the webhook templates, besides the standard ones
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+type webhook struct {
+	config   *config.WebhookNotifier
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhook returns a sender that posts the events to an HTTP endpoint
+func NewWebhook(config config.WebhookNotifier) (Sender, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("url must not be empty")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.SignatureHeader == "" {
+		config.SignatureHeader = "X-Signature-256"
+	}
+	w := &webhook{
+		config: &config,
+		client: &http.Client{},
+	}
+	if config.Template != "" {
+		var err error
+		w.template, err = template.New("webhook").Funcs(templateFuncs).Option("missingkey=error").Parse(config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	return w, nil
+}
+
+// body renders the request body for the given event
+func (w *webhook) body(event api.Event) ([]byte, error) {
+	if w.template == nil {
+		return json.Marshal(event)
+	}
+	var buf bytes.Buffer
+	if err := w.template.Execute(&buf, event); err != nil {
+		return nil, fmt.Errorf("rendering the template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Send posts the event to the webhook
+func (w *webhook) Send(ctx context.Context, event api.Event) error {
+	body, err := w.body(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, w.config.Method, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.config.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.config.Secret))
+		mac.Write(body)
+		req.Header.Set(w.config.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestWebhook(t *testing.T) {
+	event := api.Event{
+		Type:      api.EventStatusChanged,
+		Name:      "example-http",
+		Timestamp: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC),
+		Status:    &api.Status{Error: `unexpected status "503"`},
+	}
+	type expected struct {
+		body      string
+		signature string
+		header    string
+		err       bool
+	}
+	tests := []struct {
+		name     string
+		config   config.WebhookNotifier
+		status   int
+		expected expected
+	}{
+		{
+			name:   "default body",
+			status: http.StatusOK,
+			expected: expected{
+				body: `{"type":"StatusChanged","name":"example-http","timestamp":"2023-06-01T12:00:00Z","status":{"error":"unexpected status \"503\"","timestamp":"0001-01-01T00:00:00Z","duration":"0s","contiguousFailures":0,"contiguousSuccesses":0,"timeOfFirstFailure":"0001-01-01T00:00:00Z"}}`,
+			},
+		},
+		{
+			name: "template",
+			config: config.WebhookNotifier{
+				Template: `{"text": {{ printf "%s is failing: %s" .Name .Status.Error | json }}}`,
+				Headers:  map[string]string{"Authorization": "Bearer token"},
+			},
+			status: http.StatusNoContent,
+			expected: expected{
+				body:   `{"text": "example-http is failing: unexpected status \"503\""}`,
+				header: "Bearer token",
+			},
+		},
+		{
+			name: "signed",
+			config: config.WebhookNotifier{
+				Template: `{{ .Name }}`,
+				Secret:   "secret",
+			},
+			status: http.StatusOK,
+			expected: expected{
+				body:      "example-http",
+				signature: "sha256=" + sign("secret", "example-http"),
+			},
+		},
+		{
+			name:   "error",
+			status: http.StatusInternalServerError,
+			expected: expected{
+				err: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body, signature, header string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				body = string(b)
+				signature = r.Header.Get("X-Signature-256")
+				header = r.Header.Get("Authorization")
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			tt.config.URL = srv.URL
+			wh, err := NewWebhook(tt.config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = wh.Send(context.TODO(), event)
+			if (err != nil) != tt.expected.err {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expected.err {
+				return
+			}
+			if body != tt.expected.body {
+				t.Errorf("unexpected body, wanted: %s, got: %s", tt.expected.body, body)
+			}
+			if signature != tt.expected.signature {
+				t.Errorf("unexpected signature, wanted: %s, got: %s", tt.expected.signature, signature)
+			}
+			if header != tt.expected.header {
+				t.Errorf("unexpected header, wanted: %s, got: %s", tt.expected.header, header)
+			}
+		})
+	}
+
+	if _, err := NewWebhook(config.WebhookNotifier{URL: "http://example.com", Template: "{{ .Name "}); err == nil {
+		t.Errorf("expected an error for an invalid template")
+	}
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
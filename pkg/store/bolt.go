@@ -0,0 +1,242 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+var _ Store = &BoltStore{}
+
+var (
+	statusesBucket   = []byte("statuses")
+	historyBucket    = []byte("history")
+	checksBucket     = []byte("checks")
+	heartbeatsBucket = []byte("heartbeats")
+)
+
+// BoltStore persists the state of the checks in a local BoltDB file.
+// The history of each check is kept in a nested bucket, keyed by the result timestamp,
+// and bounded by the same size and retention as the in-memory history.
+type BoltStore struct {
+	db        *bolt.DB
+	size      int
+	retention time.Duration
+	// counts caches the number of results in the history of each check, so that they're not counted on every save
+	counts map[string]int
+	mu     sync.Mutex
+}
+
+// NewBoltStore opens, or creates, the BoltDB file at the given path
+func NewBoltStore(path string, history config.HistoryCfg) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{statusesBucket, historyBucket, checksBucket, heartbeatsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialise store: %w", err)
+	}
+	s := &BoltStore{
+		db:        db,
+		size:      history.Size,
+		retention: history.Retention.Duration,
+		counts:    make(map[string]int),
+	}
+	if s.size <= 0 {
+		s.size = config.DefaultHistorySize
+	}
+	if s.retention <= 0 {
+		s.retention = config.DefaultHistoryRetention
+	}
+	return s, nil
+}
+
+// Save persists the status of the given check and adds its last result to the check's history
+func (s *BoltStore) Save(name string, status api.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count, counted := s.counts[name]
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := put(tx.Bucket(statusesBucket), name, status); err != nil {
+			return err
+		}
+		b, err := tx.Bucket(historyBucket).CreateBucketIfNotExists([]byte(name))
+		if err != nil {
+			return err
+		}
+		if !counted {
+			// the bucket stats don't account for the changes in the current transaction, there are none yet
+			count = b.Stats().KeyN
+		}
+		key := timeKey(status.Timestamp)
+		if last, _ := b.Cursor().Last(); last != nil && string(last) >= string(key) {
+			// the result was already recorded
+			return nil
+		}
+		v, err := json.Marshal(status.Result())
+		if err != nil {
+			return err
+		}
+		if err := b.Put(key, v); err != nil {
+			return err
+		}
+		count, err = s.prune(b, count+1, status.Timestamp)
+		return err
+	})
+	if err == nil {
+		s.counts[name] = count
+	}
+	return err
+}
+
+// prune drops the results that are too old or exceed the maximum size,
+// it takes and returns the number of results in the bucket, only the dropped results are visited
+func (s *BoltStore) prune(b *bolt.Bucket, count int, now time.Time) (int, error) {
+	c := b.Cursor()
+	oldest := timeKey(now.Add(-s.retention))
+	for k, _ := c.First(); k != nil && (count > s.size || string(k) < string(oldest)); k, _ = c.First() {
+		if err := c.Delete(); err != nil {
+			return count, err
+		}
+		count--
+	}
+	return count, nil
+}
+
+// Statuses returns all the persisted statuses
+func (s *BoltStore) Statuses() (api.Statuses, error) {
+	statuses := make(api.Statuses)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(statusesBucket).ForEach(func(k, v []byte) error {
+			var status api.Status
+			if err := json.Unmarshal(v, &status); err != nil {
+				return fmt.Errorf("failed to decode status for %s: %w", k, err)
+			}
+			statuses[string(k)] = status
+			return nil
+		})
+	})
+	return statuses, err
+}
+
+// History returns the persisted results for the given check, oldest first
+func (s *BoltStore) History(name string) ([]api.Result, error) {
+	var results []api.Result
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(historyBucket).Bucket([]byte(name))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var result api.Result
+			if err := json.Unmarshal(v, &result); err != nil {
+				return fmt.Errorf("failed to decode result for %s: %w", name, err)
+			}
+			results = append(results, result)
+			return nil
+		})
+	})
+	return results, err
+}
+
+// SaveConfig persists the configuration of a check added at runtime
+func (s *BoltStore) SaveConfig(name string, cfg config.Config) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(checksBucket), name, cfg)
+	})
+}
+
+// Configs returns the persisted check configurations
+func (s *BoltStore) Configs() (map[string]config.Config, error) {
+	configs := make(map[string]config.Config)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(checksBucket).ForEach(func(k, v []byte) error {
+			var cfg config.Config
+			if err := json.Unmarshal(v, &cfg); err != nil {
+				return fmt.Errorf("failed to decode config for %s: %w", k, err)
+			}
+			configs[string(k)] = cfg
+			return nil
+		})
+	})
+	return configs, err
+}
+
+// SaveHeartbeat persists the pings received by a heartbeat check
+func (s *BoltStore) SaveHeartbeat(name string, state checks.HeartbeatState) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return put(tx.Bucket(heartbeatsBucket), name, state)
+	})
+}
+
+// Heartbeats returns the persisted heartbeat states
+func (s *BoltStore) Heartbeats() (map[string]checks.HeartbeatState, error) {
+	states := make(map[string]checks.HeartbeatState)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(heartbeatsBucket).ForEach(func(k, v []byte) error {
+			var state checks.HeartbeatState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return fmt.Errorf("failed to decode heartbeat for %s: %w", k, err)
+			}
+			states[string(k)] = state
+			return nil
+		})
+	})
+	return states, err
+}
+
+// Delete removes all the data persisted for the given check
+func (s *BoltStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.counts, name)
+	key := []byte(name)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{statusesBucket, checksBucket, heartbeatsBucket} {
+			if err := tx.Bucket(b).Delete(key); err != nil {
+				return err
+			}
+		}
+		if err := tx.Bucket(historyBucket).DeleteBucket(key); err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+		return nil
+	})
+}
+
+// Close closes the BoltDB file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func put(b *bolt.Bucket, key string, value interface{}) error {
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(key), v)
+}
+
+// timeKey returns a key that sorts in chronological order
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
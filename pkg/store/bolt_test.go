@@ -0,0 +1,112 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+func TestBoltStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	s, err := NewBoltStore(path, config.HistoryCfg{Size: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	for i, ok := range []bool{false, true, true} {
+		status := api.Status{
+			OK:                 ok,
+			LastOK:             ok,
+			Timestamp:          now.Add(time.Duration(i) * time.Second),
+			ContiguousFailures: 1,
+			TimeOfFirstFailure: now,
+		}
+		if err := s.Save("foo-http", status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	cfg := config.Config{
+		HTTPChecks: map[string]config.HTTPCheck{
+			"foo": {
+				URL:       "http://fake.com",
+				BaseCheck: config.BaseCheck{Interval: metav1.Duration{Duration: time.Minute}},
+			},
+		},
+	}
+	if err := s.SaveConfig("foo-http", cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.SaveHeartbeat("bar-heartbeat", checks.HeartbeatState{Created: now, LastPing: now}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// reopen the store to ensure the state is persisted
+	s, err = NewBoltStore(path, config.HistoryCfg{Size: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer s.Close()
+
+	statuses, err := s.Statuses()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status := statuses["foo-http"]; !status.OK || !status.TimeOfFirstFailure.Equal(now) {
+		t.Errorf("unexpected status: %+v", status)
+	}
+	history, err := s.History("foo-http")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 2 || !history[0].Timestamp.Equal(now.Add(time.Second)) {
+		t.Errorf("unexpected history, wanted the last 2 results, got: %+v", history)
+	}
+	// the history is still bounded after reopening the store
+	if err := s.Save("foo-http", api.Status{OK: true, LastOK: true, Timestamp: now.Add(3 * time.Second), TimeOfFirstFailure: now}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	history, _ = s.History("foo-http")
+	if len(history) != 2 || !history[0].Timestamp.Equal(now.Add(2*time.Second)) {
+		t.Errorf("unexpected history, wanted the last 2 results, got: %+v", history)
+	}
+	configs, err := s.Configs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, ok := configs["foo-http"]; !ok || !got.HTTPChecks["foo"].Equal(cfg.HTTPChecks["foo"]) {
+		t.Errorf("unexpected config, wanted: %+v, got: %+v", cfg, got)
+	}
+	heartbeats, err := s.Heartbeats()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if state := heartbeats["bar-heartbeat"]; !state.LastPing.Equal(now) {
+		t.Errorf("unexpected heartbeat state: %+v", state)
+	}
+
+	if err := s.Delete("foo-http"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statuses, _ = s.Statuses()
+	history, _ = s.History("foo-http")
+	configs, _ = s.Configs()
+	if len(statuses) != 0 || len(history) != 0 || len(configs) != 0 {
+		t.Errorf("unexpected data after deleting the check: %v, %v, %v", statuses, history, configs)
+	}
+	if err := s.Save("foo-http", api.Status{Timestamp: now}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if history, _ = s.History("foo-http"); len(history) != 1 {
+		t.Errorf("unexpected history after re-creating the check, wanted 1 result, got: %+v", history)
+	}
+}
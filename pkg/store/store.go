@@ -0,0 +1,46 @@
+// Package store persists the state of the checks, so that it survives restarts
+package store
+
+import (
+	"fmt"
+
+	"github.com/luisdavim/synthetic-checker/pkg/api"
+	"github.com/luisdavim/synthetic-checker/pkg/checks"
+	"github.com/luisdavim/synthetic-checker/pkg/config"
+)
+
+// Store persists the statuses and history of the checks, the checks added at runtime and the heartbeats received
+type Store interface {
+	// Save persists the status of the given check and adds its last result to the check's history
+	Save(name string, status api.Status) error
+	// Statuses returns all the persisted statuses
+	Statuses() (api.Statuses, error)
+	// History returns the persisted results for the given check, oldest first
+	History(name string) ([]api.Result, error)
+	// SaveConfig persists the configuration of a check added at runtime
+	SaveConfig(name string, cfg config.Config) error
+	// Configs returns the persisted check configurations
+	Configs() (map[string]config.Config, error)
+	// SaveHeartbeat persists the pings received by a heartbeat check
+	SaveHeartbeat(name string, state checks.HeartbeatState) error
+	// Heartbeats returns the persisted heartbeat states
+	Heartbeats() (map[string]checks.HeartbeatState, error)
+	// Delete removes all the data persisted for the given check
+	Delete(name string) error
+	// Close releases the resources used by the store
+	Close() error
+}
+
+// New creates a store from the given configuration,
+// it returns nil if persistence is not configured
+func New(cfg config.StoreCfg, history config.HistoryCfg) (Store, error) {
+	if cfg.Path == "" {
+		return nil, nil
+	}
+	switch cfg.Type {
+	case "", "bolt":
+		return NewBoltStore(cfg.Path, history)
+	default:
+		return nil, fmt.Errorf("unknown store type %q", cfg.Type)
+	}
+}